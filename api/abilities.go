@@ -0,0 +1,62 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"pokeproject/typeeffectiveness"
+)
+
+// AbilityResponse represents the API response for a single ability.
+type AbilityResponse struct {
+	Name        string                           `json:"name"`
+	DisplayName string                           `json:"display_name"`
+	Generation  string                           `json:"generation,omitempty"`
+	Effect      string                           `json:"effect"`
+	ShortEffect string                           `json:"short_effect"`
+	FlavorText  string                           `json:"flavor_text"`
+	Matchups    *typeeffectiveness.AbilityEffect `json:"matchups,omitempty"`
+	Pokemon     []string                         `json:"pokemon"`
+}
+
+// GetAbilityByNameCached returns an ability by API or translated name, with the
+// Pokemon of the game that can have it and its effect on type matchups.
+func GetAbilityByNameCached(w http.ResponseWriter, r *http.Request, cache *Cache) {
+	name := strings.TrimPrefix(r.URL.Path, "/api/abilities/")
+	name = strings.ToLower(strings.TrimSpace(name))
+	lang := getLang(r)
+
+	if name == "" {
+		http.Error(w, `{"error": "Ability name is required"}`, http.StatusBadRequest)
+		return
+	}
+
+	ability := cache.lookupAbility(name)
+	if ability == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Ability not found: %s", name)})
+		return
+	}
+
+	resp := AbilityResponse{
+		Name:        ability.Name,
+		DisplayName: ability.DisplayName(lang),
+		Generation:  ability.Generation.Name,
+		Effect:      ability.Effect(lang),
+		ShortEffect: ability.ShortEffect(lang),
+		FlavorText:  ability.FlavorText(lang, cache.Game.VersionGroup),
+		Pokemon:     []string{},
+	}
+	if effect, ok := typeeffectiveness.DefensiveAbility(ability.Name); ok {
+		resp.Matchups = &effect
+	}
+	for _, p := range cache.PokemonByAbility(ability.Name) {
+		resp.Pokemon = append(resp.Pokemon, p.Name)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+
+	"pokeproject/games"
+)
+
+// ReloadData handles POST /api/admin/reload. It requires the ADMIN_TOKEN
+// environment variable to be set and sent as "Authorization: Bearer <token>".
+func ReloadData(w http.ResponseWriter, r *http.Request, store *Store) {
+	token := os.Getenv("ADMIN_TOKEN")
+	provided := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Unauthorized"})
+		return
+	}
+
+	if err := store.Reload(); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Reload failed, previous data kept: " + err.Error()})
+		return
+	}
+
+	loaded := make(map[string]map[string]int)
+	for _, cache := range store.Caches() {
+		loaded[cache.Game.Key] = map[string]int{
+			"pokemon": len(cache.Pokemon),
+			"moves":   len(cache.Moves),
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(loaded)
+}
+
+// GetGames handles GET /api/games and lists the games with loaded data.
+func GetGames(w http.ResponseWriter, r *http.Request, store *Store) {
+	list := []games.Game{}
+	for _, cache := range store.Caches() {
+		list = append(list, cache.Game)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
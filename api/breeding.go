@@ -0,0 +1,228 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// maxBreedingChainLength is the most fathers a breeding chain may have.
+	maxBreedingChainLength = 4
+	// maxBreedingChains caps how many chains a breeding response lists.
+	maxBreedingChains = 50
+)
+
+// BreedingStep is one Pokemon of a breeding chain and how it knows the move.
+// The first step learns it on its own; every later one hatches with it.
+type BreedingStep struct {
+	Pokemon   string   `json:"pokemon"`
+	Method    string   `json:"method"`
+	Level     int      `json:"level,omitempty"`
+	EggGroups []string `json:"egg_groups"`
+}
+
+// BreedingResponse is the API response for an egg move breeding search.
+type BreedingResponse struct {
+	Pokemon     string           `json:"pokemon"`
+	Move        string           `json:"move"`
+	DisplayName string           `json:"display_name"`
+	EggGroups   []string         `json:"egg_groups"`
+	Chains      [][]BreedingStep `json:"chains"`
+}
+
+// breedingNode is a father reached by the breeding search, linked to the
+// father it got the move from.
+type breedingNode struct {
+	pokemon *Pokemon
+	species *Species
+	step    BreedingStep
+	parent  *breedingNode
+}
+
+// GetPokemonBreedingCached handles GET /api/pokemon/{name}/breeding?move=: the
+// chains of fathers that can pass an egg move down to a Pokemon. Each chain
+// starts with a male that learns the move by level-up, machine or tutor and
+// goes through males that hatch with it, each sharing an egg group with the
+// next. Shorter chains come first.
+func GetPokemonBreedingCached(w http.ResponseWriter, r *http.Request, cache *Cache) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/pokemon/")
+	name := strings.ToLower(strings.TrimSuffix(path, "/breeding"))
+
+	if name == "" {
+		http.Error(w, `{"error": "Pokemon name is required"}`, http.StatusBadRequest)
+		return
+	}
+
+	moveQuery := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("move")))
+	if moveQuery == "" {
+		http.Error(w, `{"error": "move is required"}`, http.StatusBadRequest)
+		return
+	}
+
+	pokemon := cache.lookupPokemon(name)
+	if pokemon == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Pokemon not found: %s", name)})
+		return
+	}
+
+	moveName, ok := cache.MoveNameIndex[moveQuery]
+	if !ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Move not found: %s", moveQuery)})
+		return
+	}
+
+	species := cache.SpeciesOf(pokemon)
+	if species == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("No species data for %s", pokemon.Name)})
+		return
+	}
+
+	species = breedingSpecies(species, cache)
+	if err := checkEggMove(pokemon, species, moveName, cache); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	resp := BreedingResponse{
+		Pokemon:     pokemon.Name,
+		Move:        moveName,
+		DisplayName: cache.Moves[moveName].DisplayName(getLang(r)),
+		EggGroups:   species.EggGroupNames(),
+		Chains:      findBreedingChains(pokemon, species, moveName, cache),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// breedingSpecies returns the species whose egg groups decide who can father
+// a species' eggs. Baby Pokemon cannot breed themselves and hatch from eggs
+// of the species they evolve into.
+func breedingSpecies(species *Species, cache *Cache) *Species {
+	if !species.IsBaby {
+		return species
+	}
+	for _, sp := range cache.Species {
+		if sp.EvolvesFromSpecies != nil && sp.EvolvesFromSpecies.Name == species.Name {
+			return sp
+		}
+	}
+	return species
+}
+
+// checkEggMove returns an error unless the Pokemon can breed and the move is
+// one of its egg moves, or one of its pre-evolutions'.
+func checkEggMove(pokemon *Pokemon, species *Species, moveName string, cache *Cache) error {
+	if !species.CanBreed() {
+		return fmt.Errorf("%s cannot inherit moves by breeding", pokemon.Name)
+	}
+	vg := cache.Game.VersionGroup
+	if pokemon.LearnsMoveBy(vg, moveName, "egg") {
+		return nil
+	}
+	for _, prevo := range cache.PreEvolutions(pokemon) {
+		if prevo.LearnsMoveBy(vg, moveName, "egg") {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not an egg move of %s in %s", moveName, pokemon.Name, cache.Game.Name)
+}
+
+// findBreedingChains searches breadth-first from the males that learn the
+// move on their own, through males that have it as an egg move, until a
+// father shares an egg group with the target. Each Pokemon is used once, in
+// the shortest chain that reaches it.
+func findBreedingChains(target *Pokemon, targetSpecies *Species, moveName string, cache *Cache) [][]BreedingStep {
+	vg := cache.Game.VersionGroup
+	chains := [][]BreedingStep{}
+
+	var frontier []*breedingNode
+	visited := make(map[*Pokemon]bool)
+	for _, p := range cache.Pokemon {
+		sp := cache.SpeciesOf(p)
+		if sp == nil || !canFather(sp) {
+			continue
+		}
+		for _, detail := range p.LearnDetails(vg, moveName) {
+			if detail.Method == "egg" {
+				continue
+			}
+			frontier = append(frontier, &breedingNode{
+				pokemon: p,
+				species: sp,
+				step: BreedingStep{
+					Pokemon:   p.Name,
+					Method:    detail.Method,
+					Level:     detail.Level,
+					EggGroups: sp.EggGroupNames(),
+				},
+			})
+			visited[p] = true
+			break
+		}
+	}
+
+	for length := 1; length <= maxBreedingChainLength && len(frontier) > 0; length++ {
+		var next []*breedingNode
+		for _, node := range frontier {
+			if node.species.SharesEggGroup(targetSpecies) && len(chains) < maxBreedingChains {
+				chains = append(chains, node.chain(target, targetSpecies))
+			}
+			if length == maxBreedingChainLength {
+				continue
+			}
+			for _, p := range cache.Pokemon {
+				if visited[p] || p == target || !p.LearnsMoveBy(vg, moveName, "egg") {
+					continue
+				}
+				sp := cache.SpeciesOf(p)
+				if sp == nil || !canFather(sp) || !sp.SharesEggGroup(node.species) {
+					continue
+				}
+				visited[p] = true
+				next = append(next, &breedingNode{
+					pokemon: p,
+					species: sp,
+					step: BreedingStep{
+						Pokemon:   p.Name,
+						Method:    "egg",
+						EggGroups: sp.EggGroupNames(),
+					},
+					parent: node,
+				})
+			}
+		}
+		frontier = next
+	}
+	return chains
+}
+
+// chain returns the steps from the first father down to the node, followed
+// by the target.
+func (n *breedingNode) chain(target *Pokemon, targetSpecies *Species) []BreedingStep {
+	var steps []BreedingStep
+	for node := n; node != nil; node = node.parent {
+		steps = append([]BreedingStep{node.step}, steps...)
+	}
+	return append(steps, BreedingStep{
+		Pokemon:   target.Name,
+		Method:    "egg",
+		EggGroups: targetSpecies.EggGroupNames(),
+	})
+}
+
+// canFather reports whether a species can be the father of an egg: it must
+// breed and have males.
+func canFather(sp *Species) bool {
+	return sp.CanBreed() && sp.GenderRate != FemaleOnly
+}
@@ -3,137 +3,589 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"unicode"
 
+	"pokeproject/games"
+	"pokeproject/typeeffectiveness"
+
 	"cloud.google.com/go/firestore"
 )
 
-// Cache holds all Pokemon and Move data in memory.
+// Cache holds all Pokemon and Move data of a game in memory.
+// The unexported indexes are built once at load time and never modified.
 type Cache struct {
-	PokemonRaw    []map[string]interface{}
-	MovesRaw      map[string]map[string]interface{}
+	Game          games.Game
+	Pokemon       []*Pokemon
+	Moves         map[string]*Move
 	MoveNameIndex map[string]string // translated name -> API name
+	Abilities     map[string]*Ability
+	Items         map[string]*Item
+	Machines      []*Machine
+	Evolutions    []*EvolutionChain
+	Species       map[string]*Species
+	Encounters    []*Encounter
+	Trainers      []games.Trainer
+
+	byName       map[string]*Pokemon
+	byID         map[int]*Pokemon
+	byRegionalID map[int]*Pokemon
+	byType       map[string][]*Pokemon
+	byAbility    map[string][]*Pokemon
+	abilityNames map[string]string          // translated name -> API name
+	itemNames    map[string]string          // translated name -> API name
+	bySpecies    map[string][]*Pokemon      // species name -> Pokemon (forms) of it
+	chainOf      map[string]*EvolutionChain // species name -> its evolution chain
+	encountersOf map[string][]*Encounter    // Pokemon name -> its encounter areas
+	byArea       map[string][]*Encounter    // location area -> its encounters
+	itemMachine  map[string]*Machine        // TM/HM item name -> machine
+	moveMachine  map[string]*Machine        // move name -> TM/HM teaching it
+	moveLearners map[string][]*Pokemon      // move name -> Pokemon that learn it in Game
+	trainerByID  map[string]int             // trainer ID -> position in Trainers
+	position     map[*Pokemon]int           // index in Pokemon, to keep results in load order
+	searchItems  []SearchMatchItem          // list representation, parallel to Pokemon
 }
 
-// NewCacheFromJSON loads data from local JSON files (no Firestore needed).
-func NewCacheFromJSON(dataDir string) (*Cache, error) {
-	cache := &Cache{
-		MovesRaw:      make(map[string]map[string]interface{}),
-		MoveNameIndex: make(map[string]string),
+// rawData holds the undecoded documents of a game, as read from JSON files
+// or Firestore. Only Pokemon and Moves are required.
+type rawData struct {
+	Pokemon    []map[string]interface{}
+	Moves      []map[string]interface{}
+	Abilities  []map[string]interface{}
+	Items      []map[string]interface{}
+	Machines   []map[string]interface{}
+	Species    []map[string]interface{}
+	Evolutions []map[string]interface{}
+	Encounters []map[string]interface{}
+}
+
+// NewCacheFromJSON loads a game's data from local JSON files (no Firestore needed).
+// The Pokemon and moves files are required; the other datasets are optional.
+func NewCacheFromJSON(dataDir string, game games.Game) (*Cache, error) {
+	var raw rawData
+	var err error
+	pokemonPath := filepath.Join(dataDir, game.PokemonFile())
+	if raw.Pokemon, err = readDataFile(pokemonPath, false); err != nil {
+		return nil, err
+	}
+	movesPath := filepath.Join(dataDir, game.MovesFile())
+	if raw.Moves, err = readDataFile(movesPath, false); err != nil {
+		return nil, err
+	}
+	if raw.Abilities, err = readDataFile(filepath.Join(dataDir, game.AbilitiesFile()), true); err != nil {
+		return nil, err
+	}
+	if raw.Species, err = readDataFile(filepath.Join(dataDir, game.SpeciesFile()), true); err != nil {
+		return nil, err
+	}
+	if raw.Evolutions, err = readDataFile(filepath.Join(dataDir, game.EvolutionsFile()), true); err != nil {
+		return nil, err
+	}
+	if raw.Encounters, err = readDataFile(filepath.Join(dataDir, game.EncountersFile()), true); err != nil {
+		return nil, err
+	}
+	if raw.Items, err = readDataFile(filepath.Join(dataDir, game.ItemsFile()), true); err != nil {
+		return nil, err
 	}
+	if raw.Machines, err = readDataFile(filepath.Join(dataDir, game.MachinesFile()), true); err != nil {
+		return nil, err
+	}
+
+	cache, err := newCache(game, raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s data in %s: %w", game.Name, dataDir, err)
+	}
+	log.Printf("Loaded %d Pokemon from %s", len(cache.Pokemon), pokemonPath)
+	log.Printf("Loaded %d moves from %s", len(cache.Moves), movesPath)
+	if len(cache.Abilities) > 0 {
+		log.Printf("Loaded %d abilities from %s", len(cache.Abilities), game.AbilitiesFile())
+	}
+	if len(cache.Species) > 0 {
+		log.Printf("Loaded %d species from %s", len(cache.Species), game.SpeciesFile())
+	}
+	if len(cache.Evolutions) > 0 {
+		log.Printf("Loaded %d evolution chains from %s", len(cache.Evolutions), game.EvolutionsFile())
+	}
+	if len(cache.Encounters) > 0 {
+		log.Printf("Loaded %d encounter areas from %s", len(cache.Encounters), game.EncountersFile())
+	}
+	if len(cache.Items) > 0 {
+		log.Printf("Loaded %d items and %d machines from %s", len(cache.Items), len(cache.Machines), game.ItemsFile())
+	}
+
+	return cache, nil
+}
 
-	// Load Pokemon
-	pokemonPath := filepath.Join(dataDir, "heartgold-pokemon.json")
-	pokemonData, err := os.ReadFile(pokemonPath)
+// readDataFile reads a JSON array of documents. A missing optional file
+// yields no documents.
+func readDataFile(path string, optional bool) ([]map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if optional && errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("could not read %s: %w", pokemonPath, err)
+		return nil, fmt.Errorf("could not read %s: %w", path, err)
+	}
+
+	var docs []map[string]interface{}
+	if err := json.Unmarshal(data, &docs); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", path, err)
+	}
+	return docs, nil
+}
+
+// NewCacheFromFirestore loads a game's data from Firestore (fallback / development).
+func NewCacheFromFirestore(client *firestore.Client, game games.Game) (*Cache, error) {
+	ctx := context.Background()
+	var raw rawData
+	var err error
+
+	log.Println("Loading Pokemon from Firestore...")
+	if raw.Pokemon, err = readCollection(ctx, client, game.PokemonCollection()); err != nil {
+		return nil, fmt.Errorf("failed to load Pokemon: %w", err)
+	}
+
+	log.Println("Loading moves from Firestore...")
+	if raw.Moves, err = readCollection(ctx, client, game.MovesCollection()); err != nil {
+		return nil, fmt.Errorf("failed to load moves: %w", err)
+	}
+
+	log.Println("Loading abilities from Firestore...")
+	if raw.Abilities, err = readCollection(ctx, client, game.AbilitiesCollection()); err != nil {
+		return nil, fmt.Errorf("failed to load abilities: %w", err)
+	}
+
+	log.Println("Loading species from Firestore...")
+	if raw.Species, err = readCollection(ctx, client, game.SpeciesCollection()); err != nil {
+		return nil, fmt.Errorf("failed to load species: %w", err)
+	}
+
+	log.Println("Loading evolution chains from Firestore...")
+	if raw.Evolutions, err = readCollection(ctx, client, game.EvolutionsCollection()); err != nil {
+		return nil, fmt.Errorf("failed to load evolution chains: %w", err)
+	}
+
+	log.Println("Loading encounters from Firestore...")
+	if raw.Encounters, err = readCollection(ctx, client, game.EncountersCollection()); err != nil {
+		return nil, fmt.Errorf("failed to load encounters: %w", err)
+	}
+
+	log.Println("Loading items from Firestore...")
+	if raw.Items, err = readCollection(ctx, client, game.ItemsCollection()); err != nil {
+		return nil, fmt.Errorf("failed to load items: %w", err)
+	}
+	if raw.Machines, err = readCollection(ctx, client, game.MachinesCollection()); err != nil {
+		return nil, fmt.Errorf("failed to load machines: %w", err)
 	}
 
-	var pokemonRaw []map[string]interface{}
-	if err := json.Unmarshal(pokemonData, &pokemonRaw); err != nil {
-		return nil, fmt.Errorf("could not parse %s: %w", pokemonPath, err)
+	cache, err := newCache(game, raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s data in Firestore: %w", game.Name, err)
 	}
+	log.Printf("Loaded %d Pokemon and %d moves from Firestore", len(cache.Pokemon), len(cache.Moves))
+
+	return cache, nil
+}
 
-	// Normalize keys (Firestore stores Go struct fields in PascalCase)
-	for _, p := range pokemonRaw {
-		cache.PokemonRaw = append(cache.PokemonRaw, normalizeKeys(p))
+// readCollection returns the data of every document in a Firestore collection.
+func readCollection(ctx context.Context, client *firestore.Client, collection string) ([]map[string]interface{}, error) {
+	docs, err := client.Collection(collection).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
 	}
-	log.Printf("Loaded %d Pokemon from %s", len(cache.PokemonRaw), pokemonPath)
+	var raw []map[string]interface{}
+	for _, doc := range docs {
+		raw = append(raw, doc.Data())
+	}
+	return raw, nil
+}
 
-	// Load Moves
-	movesPath := filepath.Join(dataDir, "heartgold-moves.json")
-	movesData, err := os.ReadFile(movesPath)
+// newCache decodes and validates raw documents. Every invalid document is
+// reported, so a single load shows all data problems at once.
+func newCache(game games.Game, raw rawData) (*Cache, error) {
+	cache := &Cache{
+		Game:          game,
+		Moves:         make(map[string]*Move),
+		MoveNameIndex: make(map[string]string),
+		Abilities:     make(map[string]*Ability),
+		Items:         make(map[string]*Item),
+		Species:       make(map[string]*Species),
+		Trainers:      game.Trainers(),
+	}
+	chart, err := typeeffectiveness.ForGeneration(game.Generation)
 	if err != nil {
-		return nil, fmt.Errorf("could not read %s: %w", movesPath, err)
+		return nil, err
 	}
+	var errs []error
 
-	var movesRaw []map[string]interface{}
-	if err := json.Unmarshal(movesData, &movesRaw); err != nil {
-		return nil, fmt.Errorf("could not parse %s: %w", movesPath, err)
+	seen := make(map[string]bool)
+	for i, doc := range raw.Pokemon {
+		// Normalize keys (Firestore stores Go struct fields in PascalCase)
+		var p Pokemon
+		if err := decodeRecord(normalizeKeys(doc), &p); err != nil {
+			errs = append(errs, fmt.Errorf("pokemon[%d]: %w", i, err))
+			continue
+		}
+		p.Name = strings.ToLower(p.Name)
+		if err := p.resolveTypes(game.Generation); err != nil {
+			errs = append(errs, fmt.Errorf("pokemon[%d] (%s): %w", i, p.Name, err))
+			continue
+		}
+		if err := p.resolveAbilities(game.Generation); err != nil {
+			errs = append(errs, fmt.Errorf("pokemon[%d] (%s): %w", i, p.Name, err))
+			continue
+		}
+		if err := p.validate(chart); err != nil {
+			errs = append(errs, fmt.Errorf("pokemon[%d] (%s): %w", i, p.Name, err))
+			continue
+		}
+		if seen[p.Name] {
+			errs = append(errs, fmt.Errorf("pokemon[%d] (%s): duplicate name", i, p.Name))
+			continue
+		}
+		seen[p.Name] = true
+		cache.Pokemon = append(cache.Pokemon, &p)
 	}
 
-	for _, m := range movesRaw {
-		name := ""
-		if n, ok := m["name"].(string); ok {
-			name = n
+	for i, doc := range raw.Moves {
+		var m Move
+		if err := decodeRecord(normalizeKeys(doc), &m); err != nil {
+			errs = append(errs, fmt.Errorf("move[%d]: %w", i, err))
+			continue
 		}
-		if name == "" {
+		if m.Name == "" {
+			// Leftover documents without a name (e.g. Firestore test docs) are skipped.
 			continue
 		}
-		lowerName := strings.ToLower(name)
-		cache.MovesRaw[lowerName] = m
-		cache.MoveNameIndex[lowerName] = lowerName
-		if names, ok := m["names"].([]interface{}); ok {
-			for _, n := range names {
-				nameMap, ok := n.(map[string]interface{})
-				if !ok {
-					continue
-				}
-				translatedName := strings.ToLower(getStringField(nameMap, "name"))
-				if translatedName != "" {
-					cache.MoveNameIndex[translatedName] = lowerName
-				}
+		m.Name = strings.ToLower(m.Name)
+		if err := m.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("move[%d] (%s): %w", i, m.Name, err))
+			continue
+		}
+		cache.Moves[m.Name] = &m
+		cache.MoveNameIndex[m.Name] = m.Name
+		for _, n := range m.Names {
+			translatedName := strings.ToLower(n.Name)
+			if translatedName != "" {
+				cache.MoveNameIndex[translatedName] = m.Name
 			}
 		}
 	}
-	log.Printf("Loaded %d moves from %s", len(cache.MovesRaw), movesPath)
 
+	for i, doc := range raw.Abilities {
+		var a Ability
+		if err := decodeRecord(normalizeKeys(doc), &a); err != nil {
+			errs = append(errs, fmt.Errorf("ability[%d]: %w", i, err))
+			continue
+		}
+		if a.Name == "" {
+			continue
+		}
+		a.Name = strings.ToLower(a.Name)
+		cache.Abilities[a.Name] = &a
+	}
+
+	for i, doc := range raw.Species {
+		var sp Species
+		if err := decodeRecord(normalizeKeys(doc), &sp); err != nil {
+			errs = append(errs, fmt.Errorf("species[%d]: %w", i, err))
+			continue
+		}
+		if sp.Name == "" {
+			continue
+		}
+		cache.Species[sp.Name] = &sp
+	}
+
+	for i, doc := range raw.Evolutions {
+		var chain EvolutionChain
+		if err := decodeRecord(normalizeKeys(doc), &chain); err != nil {
+			errs = append(errs, fmt.Errorf("evolution chain[%d]: %w", i, err))
+			continue
+		}
+		if chain.Chain.Species.Name == "" {
+			errs = append(errs, fmt.Errorf("evolution chain[%d]: missing base species", i))
+			continue
+		}
+		cache.Evolutions = append(cache.Evolutions, &chain)
+	}
+
+	for i, doc := range raw.Encounters {
+		var enc Encounter
+		if err := decodeRecord(normalizeKeys(doc), &enc); err != nil {
+			errs = append(errs, fmt.Errorf("encounter[%d]: %w", i, err))
+			continue
+		}
+		if enc.Pokemon == "" || enc.LocationArea.Name == "" {
+			errs = append(errs, fmt.Errorf("encounter[%d]: missing pokemon or location area", i))
+			continue
+		}
+		cache.Encounters = append(cache.Encounters, &enc)
+	}
+
+	for i, doc := range raw.Items {
+		var it Item
+		if err := decodeRecord(normalizeKeys(doc), &it); err != nil {
+			errs = append(errs, fmt.Errorf("item[%d]: %w", i, err))
+			continue
+		}
+		if it.Name == "" {
+			continue
+		}
+		it.Name = strings.ToLower(it.Name)
+		cache.Items[it.Name] = &it
+	}
+
+	for i, doc := range raw.Machines {
+		var m Machine
+		if err := decodeRecord(normalizeKeys(doc), &m); err != nil {
+			errs = append(errs, fmt.Errorf("machine[%d]: %w", i, err))
+			continue
+		}
+		if m.Item.Name == "" || m.Move.Name == "" {
+			errs = append(errs, fmt.Errorf("machine[%d]: missing item or move", i))
+			continue
+		}
+		if m.VersionGroup.Name != "" && m.VersionGroup.Name != game.VersionGroup {
+			continue
+		}
+		cache.Machines = append(cache.Machines, &m)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	cache.buildIndexes()
 	return cache, nil
 }
 
-// NewCacheFromFirestore loads data from Firestore (fallback / development).
-func NewCacheFromFirestore(client *firestore.Client) *Cache {
-	ctx := context.Background()
-	cache := &Cache{
-		MovesRaw:      make(map[string]map[string]interface{}),
-		MoveNameIndex: make(map[string]string),
+// buildIndexes builds the lookup indexes used by the handlers.
+func (c *Cache) buildIndexes() {
+	c.byName = make(map[string]*Pokemon, len(c.Pokemon))
+	c.byID = make(map[int]*Pokemon, len(c.Pokemon))
+	c.byRegionalID = make(map[int]*Pokemon, len(c.Pokemon))
+	c.byType = make(map[string][]*Pokemon)
+	c.byAbility = make(map[string][]*Pokemon)
+	c.abilityNames = make(map[string]string, len(c.Abilities))
+	c.itemNames = make(map[string]string, len(c.Items))
+	c.bySpecies = make(map[string][]*Pokemon, len(c.Pokemon))
+	c.chainOf = make(map[string]*EvolutionChain)
+	c.encountersOf = make(map[string][]*Encounter)
+	c.byArea = make(map[string][]*Encounter)
+	c.itemMachine = make(map[string]*Machine, len(c.Machines))
+	c.moveMachine = make(map[string]*Machine, len(c.Machines))
+	c.moveLearners = make(map[string][]*Pokemon)
+	c.position = make(map[*Pokemon]int, len(c.Pokemon))
+	c.searchItems = make([]SearchMatchItem, 0, len(c.Pokemon))
+
+	for i, p := range c.Pokemon {
+		c.byName[p.Name] = p
+		c.byID[p.ID] = p
+		if p.RegionalID > 0 {
+			c.byRegionalID[p.RegionalID] = p
+		}
+		for _, t := range p.Types {
+			c.byType[t.Type.Name] = append(c.byType[t.Type.Name], p)
+		}
+		for _, a := range p.Abilities {
+			c.byAbility[a.Ability.Name] = append(c.byAbility[a.Ability.Name], p)
+		}
+		species := p.Species.Name
+		if species == "" {
+			species = p.Name
+		}
+		c.bySpecies[species] = append(c.bySpecies[species], p)
+		for _, learned := range p.Moves.ForVersionGroup(c.Game.VersionGroup) {
+			c.moveLearners[learned.Name] = append(c.moveLearners[learned.Name], p)
+		}
+		c.position[p] = i
+		c.searchItems = append(c.searchItems, buildSearchMatchItem(p))
 	}
 
-	log.Println("Loading Pokemon from Firestore...")
-	pokemonDocs, err := client.Collection("heartgold-pokemon").Documents(ctx).GetAll()
-	if err != nil {
-		log.Fatalf("Failed to load Pokemon: %v", err)
+	for _, a := range c.Abilities {
+		c.abilityNames[a.Name] = a.Name
+		for _, n := range a.Names {
+			if translated := strings.ToLower(n.Name); translated != "" {
+				c.abilityNames[translated] = a.Name
+			}
+		}
 	}
-	for _, doc := range pokemonDocs {
-		cache.PokemonRaw = append(cache.PokemonRaw, normalizeKeys(doc.Data()))
+
+	for _, chain := range c.Evolutions {
+		for _, species := range chain.Species() {
+			c.chainOf[species] = chain
+		}
+	}
+	for i, p := range c.Pokemon {
+		if chain := c.EvolutionChain(p); chain != nil {
+			c.searchItems[i].FamilyID = chain.ID
+		}
 	}
-	log.Printf("Loaded %d Pokemon from Firestore", len(cache.PokemonRaw))
 
-	log.Println("Loading moves from Firestore...")
-	moveDocs, err := client.Collection("heartgold-moves").Documents(ctx).GetAll()
-	if err != nil {
-		log.Fatalf("Failed to load moves: %v", err)
-	}
-	for _, doc := range moveDocs {
-		data := doc.Data()
-		name := getStringField(data, "name")
-		if name == "" {
-			continue
-		}
-		lowerName := strings.ToLower(name)
-		cache.MovesRaw[lowerName] = data
-		cache.MoveNameIndex[lowerName] = lowerName
-		if names, ok := data["names"].([]interface{}); ok {
-			for _, n := range names {
-				nameMap, ok := n.(map[string]interface{})
-				if !ok {
-					continue
-				}
-				translatedName := strings.ToLower(getStringField(nameMap, "name"))
-				if translatedName != "" {
-					cache.MoveNameIndex[translatedName] = lowerName
-				}
+	c.trainerByID = make(map[string]int, len(c.Trainers))
+	for i, t := range c.Trainers {
+		c.trainerByID[t.ID] = i
+	}
+
+	for _, enc := range c.Encounters {
+		c.encountersOf[enc.Pokemon] = append(c.encountersOf[enc.Pokemon], enc)
+		c.byArea[enc.LocationArea.Name] = append(c.byArea[enc.LocationArea.Name], enc)
+	}
+
+	for _, it := range c.Items {
+		c.itemNames[it.Name] = it.Name
+		for _, n := range it.Names {
+			if translated := strings.ToLower(n.Name); translated != "" {
+				c.itemNames[translated] = it.Name
 			}
 		}
 	}
-	log.Printf("Loaded %d moves from Firestore", len(cache.MovesRaw))
+	for _, m := range c.Machines {
+		c.itemMachine[m.Item.Name] = m
+		c.moveMachine[m.Move.Name] = m
+	}
+}
+
+// PokemonByName returns the Pokemon with the given (lowercase) name, or nil.
+func (c *Cache) PokemonByName(name string) *Pokemon {
+	return c.byName[name]
+}
+
+// PokemonByID returns the Pokemon with the given National Pokédex number, or nil.
+func (c *Cache) PokemonByID(id int) *Pokemon {
+	return c.byID[id]
+}
+
+// PokemonByRegionalID returns the Pokemon with the given regional Pokédex number, or nil.
+func (c *Cache) PokemonByRegionalID(id int) *Pokemon {
+	return c.byRegionalID[id]
+}
+
+// PokemonByType returns every Pokemon with the given type, in load order.
+func (c *Cache) PokemonByType(typeName string) []*Pokemon {
+	return c.byType[typeName]
+}
+
+// MoveLearners returns every Pokemon that can learn a move in the game, in load order.
+func (c *Cache) MoveLearners(moveName string) []*Pokemon {
+	return c.moveLearners[moveName]
+}
+
+// PokemonByAbility returns every Pokemon that can have an ability, in load order.
+func (c *Cache) PokemonByAbility(abilityName string) []*Pokemon {
+	return c.byAbility[abilityName]
+}
+
+// lookupAbility resolves an ability by API or translated name. Abilities
+// without ingested details are still resolved by API name when some Pokemon
+// has them, with only the name set.
+func (c *Cache) lookupAbility(key string) *Ability {
+	if name, ok := c.abilityNames[key]; ok {
+		return c.Abilities[name]
+	}
+	if len(c.byAbility[key]) > 0 {
+		return &Ability{Name: key}
+	}
+	return nil
+}
+
+// lookupItem resolves an item by API or translated name, or returns nil.
+func (c *Cache) lookupItem(key string) *Item {
+	return c.Items[c.itemNames[key]]
+}
+
+// MachineForItem returns the machine of a TM or HM item, or nil.
+func (c *Cache) MachineForItem(itemName string) *Machine {
+	return c.itemMachine[itemName]
+}
+
+// MachineForMove returns the TM or HM that teaches a move in the game, or nil.
+func (c *Cache) MachineForMove(moveName string) *Machine {
+	return c.moveMachine[moveName]
+}
+
+// PokemonBySpecies returns the Pokemon of a species (one per form), in load order.
+func (c *Cache) PokemonBySpecies(species string) []*Pokemon {
+	return c.bySpecies[species]
+}
+
+// SpeciesOf returns the species data of a Pokemon, or nil if it is not loaded.
+func (c *Cache) SpeciesOf(p *Pokemon) *Species {
+	if sp, ok := c.Species[p.Species.Name]; ok {
+		return sp
+	}
+	return c.Species[p.Name]
+}
+
+// EncountersOf returns the location areas where a Pokemon is found in the
+// wild, in load order.
+func (c *Cache) EncountersOf(p *Pokemon) []*Encounter {
+	return c.encountersOf[p.Name]
+}
+
+// AreaEncounters returns the encounters of a location area, or nil if the
+// area is unknown.
+func (c *Cache) AreaEncounters(area string) []*Encounter {
+	return c.byArea[area]
+}
+
+// Trainer returns the trainer with the given ID, or nil.
+func (c *Cache) Trainer(id string) *games.Trainer {
+	i, ok := c.trainerByID[id]
+	if !ok {
+		return nil
+	}
+	return &c.Trainers[i]
+}
+
+// EvolutionChain returns the evolution chain of a Pokemon, or nil if it is not loaded.
+func (c *Cache) EvolutionChain(p *Pokemon) *EvolutionChain {
+	species := p.Species.Name
+	if species == "" {
+		species = p.Name
+	}
+	return c.chainOf[species]
+}
+
+// PreEvolutions returns the loaded Pokemon a Pokemon evolves from, nearest
+// first (e.g. pikachu then pichu for raichu).
+func (c *Cache) PreEvolutions(p *Pokemon) []*Pokemon {
+	chain := c.EvolutionChain(p)
+	if chain == nil {
+		return nil
+	}
+	species := p.Species.Name
+	if species == "" {
+		species = p.Name
+	}
+	var prevos []*Pokemon
+	for {
+		_, parent := findChainLink(&chain.Chain, nil, species)
+		if parent == nil {
+			return prevos
+		}
+		species = parent.Species.Name
+		if forms := c.PokemonBySpecies(species); len(forms) > 0 {
+			prevos = append(prevos, forms[0])
+		}
+	}
+}
 
-	return cache
+// lookupPokemon resolves a Pokemon by name or by National Pokédex number.
+func (c *Cache) lookupPokemon(key string) *Pokemon {
+	if p := c.PokemonByName(key); p != nil {
+		return p
+	}
+	if id, err := strconv.Atoi(key); err == nil {
+		return c.PokemonByID(id)
+	}
+	return nil
 }
 
 // normalizeKeys converts PascalCase keys to snake_case recursively.
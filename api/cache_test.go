@@ -0,0 +1,221 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"pokeproject/games"
+)
+
+// Sizes of the synthetic dataset, close to a full Gen IV national dex.
+const (
+	benchPokemon      = 493
+	benchMoves        = 467
+	benchMovesPerMon  = 60
+	benchLookupTarget = "pokemon-480"
+)
+
+var benchTypes = []string{
+	"normal", "fire", "water", "electric", "grass", "ice", "fighting", "poison", "ground",
+	"flying", "psychic", "bug", "rock", "ghost", "dragon", "dark", "steel",
+}
+
+// benchCache builds a cache from synthetic documents through newCache, so the
+// indexes are the ones the server uses.
+func benchCache(b *testing.B) *Cache {
+	b.Helper()
+	game, err := games.Lookup("")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	var raw rawData
+	for m := 1; m <= benchMoves; m++ {
+		raw.Moves = append(raw.Moves, map[string]interface{}{
+			"id":           m,
+			"name":         fmt.Sprintf("move-%d", m),
+			"type":         map[string]interface{}{"name": benchTypes[m%len(benchTypes)]},
+			"damage_class": map[string]interface{}{"name": "physical"},
+			"power":        60,
+			"pp":           10,
+			"names": []interface{}{
+				map[string]interface{}{"name": fmt.Sprintf("Move %d", m), "language": map[string]interface{}{"name": "en"}},
+			},
+		})
+	}
+	for i := 1; i <= benchPokemon; i++ {
+		var stats, moves []interface{}
+		for _, s := range standardStats {
+			stats = append(stats, map[string]interface{}{"base_stat": 50, "stat": map[string]interface{}{"name": s}})
+		}
+		for k := 0; k < benchMovesPerMon; k++ {
+			moves = append(moves, map[string]interface{}{
+				"move": map[string]interface{}{"name": fmt.Sprintf("move-%d", (i*7+k)%benchMoves+1)},
+				"version_group_details": []interface{}{map[string]interface{}{
+					"level_learned_at":  k,
+					"move_learn_method": map[string]interface{}{"name": "level-up"},
+					"version_group":     map[string]interface{}{"name": game.VersionGroup},
+				}},
+			})
+		}
+		raw.Pokemon = append(raw.Pokemon, map[string]interface{}{
+			"id":          i,
+			"name":        fmt.Sprintf("pokemon-%d", i),
+			"regional_id": i,
+			"types": []interface{}{
+				map[string]interface{}{"slot": 1, "type": map[string]interface{}{"name": benchTypes[i%len(benchTypes)]}},
+				map[string]interface{}{"slot": 2, "type": map[string]interface{}{"name": benchTypes[(i/3)%len(benchTypes)]}},
+			},
+			"stats": stats,
+			"moves": moves,
+		})
+	}
+
+	cache, err := newCache(game, raw)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return cache
+}
+
+// findPokemonScan is the linear name lookup the indexes replaced.
+func findPokemonScan(c *Cache, name string) *Pokemon {
+	for _, p := range c.Pokemon {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// searchScan is SearchCached's matching as it was before the indexes: every
+// Pokemon is converted and checked against the name, type and moves.
+func searchScan(w io.Writer, c *Cache, query string) {
+	matchedType := typeTranslations[query]
+	matchingMoves := make(map[string]bool)
+	for translatedName, apiName := range c.MoveNameIndex {
+		if strings.Contains(translatedName, query) {
+			matchingMoves[apiName] = true
+		}
+	}
+
+	resp := SearchResponse{Query: query}
+	for _, p := range c.Pokemon {
+		item := buildSearchMatchItem(p)
+		if strings.Contains(strings.ToLower(item.Name), query) {
+			match := item
+			match.MatchReason = "name"
+			resp.Results.ByName = append(resp.Results.ByName, match)
+		}
+		if matchedType != "" && p.HasType(matchedType) {
+			match := item
+			match.MatchReason = "type"
+			resp.Results.ByType = append(resp.Results.ByType, match)
+		}
+		if matchedMove := pokemonLearnsMove(p, c.Game.VersionGroup, matchingMoves); matchedMove != "" {
+			match := item
+			match.MatchReason = "move"
+			match.MatchedMove = c.Moves[matchedMove].DisplayName("en")
+			resp.Results.ByMove = append(resp.Results.ByMove, match)
+		}
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+var benchQueries = []string{"pokemon-4", "fire", "move-12"}
+
+func BenchmarkPokemonByName(b *testing.B) {
+	cache := benchCache(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if cache.PokemonByName(benchLookupTarget) == nil {
+			b.Fatal("not found")
+		}
+	}
+}
+
+func BenchmarkPokemonByNameScan(b *testing.B) {
+	cache := benchCache(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if findPokemonScan(cache, benchLookupTarget) == nil {
+			b.Fatal("not found")
+		}
+	}
+}
+
+func BenchmarkPokemonByType(b *testing.B) {
+	cache := benchCache(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = cache.PokemonByType("dragon")
+	}
+}
+
+func BenchmarkPokemonByTypeScan(b *testing.B) {
+	cache := benchCache(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var matches []*Pokemon
+		for _, p := range cache.Pokemon {
+			if p.HasType("dragon") {
+				matches = append(matches, p)
+			}
+		}
+		_ = matches
+	}
+}
+
+func BenchmarkMoveLearners(b *testing.B) {
+	cache := benchCache(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = cache.MoveLearners("move-100")
+	}
+}
+
+func BenchmarkMoveLearnersScan(b *testing.B) {
+	cache := benchCache(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var learners []*Pokemon
+		for _, p := range cache.Pokemon {
+			if p.LearnsMove(cache.Game.VersionGroup, "move-100") {
+				learners = append(learners, p)
+			}
+		}
+		_ = learners
+	}
+}
+
+func BenchmarkSearchCached(b *testing.B) {
+	cache := benchCache(b)
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	for _, query := range benchQueries {
+		b.Run(query, func(b *testing.B) {
+			r := httptest.NewRequest(http.MethodGet, "/api/search?q="+query, nil)
+			for i := 0; i < b.N; i++ {
+				SearchCached(httptest.NewRecorder(), r, cache)
+			}
+		})
+	}
+}
+
+func BenchmarkSearchScan(b *testing.B) {
+	cache := benchCache(b)
+	for _, query := range benchQueries {
+		b.Run(query, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				searchScan(io.Discard, cache, query)
+			}
+		})
+	}
+}
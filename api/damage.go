@@ -0,0 +1,297 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+
+	"pokeproject/damage"
+	"pokeproject/stats"
+	"pokeproject/typeeffectiveness"
+)
+
+// defaultBattleLevel is the level used when a damage request does not set one.
+const defaultBattleLevel = 50
+
+// BattlerRequest is one side of a damage calculation.
+type BattlerRequest struct {
+	Pokemon string `json:"pokemon"`
+	Level   int    `json:"level"`
+	Nature  string `json:"nature,omitempty"`
+	Ability string `json:"ability,omitempty"`
+	Item    string `json:"item,omitempty"`
+	Burned  bool   `json:"burned,omitempty"`
+}
+
+// DamageRequest is the body of POST /api/damage.
+type DamageRequest struct {
+	Attacker BattlerRequest `json:"attacker"`
+	Defender BattlerRequest `json:"defender"`
+	Move     string         `json:"move"`
+	Critical bool           `json:"critical"`
+}
+
+// BattlerStats are the in-battle stats used for one side of a calculation:
+// the attacking stat of the attacker and the defending stat of the defender,
+// after held items and abilities.
+type BattlerStats struct {
+	Pokemon string   `json:"pokemon"`
+	Level   int      `json:"level"`
+	Types   []string `json:"types"`
+	Ability string   `json:"ability"`
+	Item    string   `json:"item,omitempty"`
+	HP      int      `json:"hp"`
+	Attack  int      `json:"attack,omitempty"`
+	Defense int      `json:"defense,omitempty"`
+}
+
+// DamageResponse is the API response for a damage calculation.
+type DamageResponse struct {
+	Attacker      BattlerStats `json:"attacker"`
+	Defender      BattlerStats `json:"defender"`
+	Move          string       `json:"move"`
+	DisplayName   string       `json:"display_name"`
+	Type          string       `json:"type"`
+	DamageClass   string       `json:"damage_class"`
+	Power         int          `json:"power"`
+	STAB          bool         `json:"stab"`
+	Effectiveness float64      `json:"effectiveness"`
+	Critical      bool         `json:"critical"`
+	Rolls         []int        `json:"rolls"`
+	MinDamage     int          `json:"min_damage"`
+	MaxDamage     int          `json:"max_damage"`
+	MinPercent    float64      `json:"min_percent"`
+	MaxPercent    float64      `json:"max_percent"`
+}
+
+// CalculateDamageCached handles POST /api/damage using the in-memory cache.
+// Stats are computed from base stats at the requested level (50 by default)
+// and nature (neutral by default) with perfect IVs and no EVs. The defender's
+// ability (its first regular ability by default) can block the hit or, like
+// Thick Fat, scale the attacking stat or the move's power, and held items
+// apply their battle modifiers. The type chart is selected with ?gen= and
+// defaults to the game's generation.
+func CalculateDamageCached(w http.ResponseWriter, r *http.Request, cache *Cache) {
+	var req DamageRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	chart, err := getChart(r, cache.Game.Generation)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	resp, err := calculateDamage(req, cache, chart, getLang(r))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// calculateDamage resolves a damage request against the cache and runs the
+// damage formula.
+func calculateDamage(req DamageRequest, cache *Cache, chart *typeeffectiveness.Chart, lang string) (DamageResponse, error) {
+	attacker, err := resolveBattler("attacker", req.Attacker, cache)
+	if err != nil {
+		return DamageResponse{}, err
+	}
+	defender, err := resolveBattler("defender", req.Defender, cache)
+	if err != nil {
+		return DamageResponse{}, err
+	}
+
+	moveName, ok := cache.MoveNameIndex[strings.ToLower(strings.TrimSpace(req.Move))]
+	if !ok {
+		return DamageResponse{}, fmt.Errorf("move not found: %s", req.Move)
+	}
+	move := cache.Moves[moveName]
+	class := damage.Category(cache.Game.Generation, move.Type.Name, move.DamageClass.Name)
+	if class == "status" {
+		return DamageResponse{}, fmt.Errorf("%s is a status move", moveName)
+	}
+	if move.Power == nil || *move.Power <= 0 {
+		return DamageResponse{}, fmt.Errorf("%s has no fixed base power", moveName)
+	}
+	if !chart.IsValidType(move.Type.Name) {
+		return DamageResponse{}, fmt.Errorf("type %s does not exist in generation %d", move.Type.Name, chart.Generation)
+	}
+
+	atkStat, defStat := "attack", "defense"
+	if class == "special" {
+		atkStat, defStat = "special-attack", "special-defense"
+	}
+
+	params := damage.Params{
+		Level:    attacker.level,
+		Power:    *move.Power,
+		Attack:   attacker.stats.Get(atkStat),
+		Defense:  defender.stats.Get(defStat),
+		STAB:     attacker.pokemon.HasType(move.Type.Name),
+		Critical: req.Critical,
+		Burned:   req.Attacker.Burned,
+		Physical: class == "physical",
+	}
+	effectiveness := 1.0
+	for _, t := range defender.pokemon.TypeNames() {
+		factor := 1.0
+		if chart.IsValidType(t) {
+			factor = chart.GetEffectiveness(move.Type.Name, t)
+		}
+		params.Effectiveness = append(params.Effectiveness, factor)
+		effectiveness *= factor
+	}
+	// Only immunities are matchups; abilities that scale damage act on the
+	// attacking stat or the move's power below
+	if typeeffectiveness.ApplyAbility(defender.ability, move.Type.Name, effectiveness) == 0 {
+		params.Modifiers = append(params.Modifiers, 0)
+		effectiveness = 0
+	}
+
+	for _, m := range damage.HolderModifiers(attacker.item, attacker.pokemon.Name) {
+		switch {
+		case m.Kind == damage.ModifierStat && m.Stat == atkStat:
+			params.Attack = int(float64(params.Attack) * m.Multiplier)
+		case m.Kind == damage.ModifierType && m.Type == move.Type.Name,
+			m.Kind == damage.ModifierCategory && m.DamageClass == class:
+			params.Power = int(float64(params.Power) * m.Multiplier)
+		case m.Kind == damage.ModifierBaseDamage:
+			params.PreRandom = append(params.PreRandom, m.Multiplier)
+		case m.Kind == damage.ModifierDamage && (!m.SuperEffectiveOnly || effectiveness > 1):
+			params.Modifiers = append(params.Modifiers, m.Multiplier)
+		}
+	}
+	for _, m := range damage.HolderModifiers(defender.item, defender.pokemon.Name) {
+		if m.Kind == damage.ModifierStat && m.Stat == defStat {
+			params.Defense = int(float64(params.Defense) * m.Multiplier)
+		}
+	}
+	for _, m := range damage.DefenderAbilityModifiers(defender.ability) {
+		switch {
+		case m.Kind == damage.ModifierAttack && m.Type == move.Type.Name:
+			params.Attack = int(float64(params.Attack) * m.Multiplier)
+		case m.Kind == damage.ModifierType && m.Type == move.Type.Name:
+			params.Power = int(float64(params.Power) * m.Multiplier)
+		}
+	}
+
+	result, err := damage.Calculate(params)
+	if err != nil {
+		return DamageResponse{}, err
+	}
+
+	hp := defender.stats.HP
+	return DamageResponse{
+		Attacker: BattlerStats{
+			Pokemon: attacker.pokemon.Name,
+			Level:   attacker.level,
+			Types:   attacker.pokemon.TypeNames(),
+			Ability: attacker.ability,
+			Item:    attacker.item,
+			HP:      attacker.stats.HP,
+			Attack:  params.Attack,
+		},
+		Defender: BattlerStats{
+			Pokemon: defender.pokemon.Name,
+			Level:   defender.level,
+			Types:   defender.pokemon.TypeNames(),
+			Ability: defender.ability,
+			Item:    defender.item,
+			HP:      hp,
+			Defense: params.Defense,
+		},
+		Move:          move.Name,
+		DisplayName:   move.DisplayName(lang),
+		Type:          move.Type.Name,
+		DamageClass:   class,
+		Power:         params.Power,
+		STAB:          params.STAB,
+		Effectiveness: effectiveness,
+		Critical:      req.Critical,
+		Rolls:         result.Rolls,
+		MinDamage:     result.Min,
+		MaxDamage:     result.Max,
+		MinPercent:    percentOf(result.Min, hp),
+		MaxPercent:    percentOf(result.Max, hp),
+	}, nil
+}
+
+// battler is a resolved side of a damage calculation.
+type battler struct {
+	pokemon *Pokemon
+	level   int
+	ability string
+	item    string
+	stats   stats.Stats
+}
+
+// resolveBattler looks up a Pokemon, validates its level, nature, ability and
+// held item and computes its stats.
+func resolveBattler(side string, req BattlerRequest, cache *Cache) (battler, error) {
+	name := strings.ToLower(strings.TrimSpace(req.Pokemon))
+	if name == "" {
+		return battler{}, fmt.Errorf("%s: pokemon is required", side)
+	}
+	pokemon := cache.lookupPokemon(name)
+	if pokemon == nil {
+		return battler{}, fmt.Errorf("%s: Pokemon not found: %s", side, name)
+	}
+	level := req.Level
+	if level == 0 {
+		level = defaultBattleLevel
+	}
+	if err := stats.ValidateLevel(level); err != nil {
+		return battler{}, fmt.Errorf("%s: %v", side, err)
+	}
+	nature, err := stats.LookupNature(req.Nature)
+	if err != nil {
+		return battler{}, fmt.Errorf("%s: %v", side, err)
+	}
+	ability, err := resolveAbility(pokemon, req.Ability, cache)
+	if err != nil {
+		return battler{}, fmt.Errorf("%s: %v", side, err)
+	}
+	item, err := resolveHeldItem(req.Item, cache)
+	if err != nil {
+		return battler{}, fmt.Errorf("%s: %v", side, err)
+	}
+	values := stats.Calculate(pokemon.BaseStats(), level, stats.Uniform(stats.MaxIV), stats.Stats{}, nature)
+	return battler{pokemon: pokemon, level: level, ability: ability, item: item, stats: values}, nil
+}
+
+// resolveHeldItem resolves a held item by API or translated name. Items with
+// battle modifiers are accepted by API name even without ingested item data.
+func resolveHeldItem(requested string, cache *Cache) (string, error) {
+	requested = strings.ToLower(strings.TrimSpace(requested))
+	if requested == "" {
+		return "", nil
+	}
+	if item := cache.lookupItem(requested); item != nil {
+		return item.Name, nil
+	}
+	if damage.ItemModifiers(requested) != nil {
+		return requested, nil
+	}
+	return "", fmt.Errorf("item not found: %s", requested)
+}
+
+// percentOf returns damage as a percentage of hp, rounded to one decimal.
+func percentOf(damage, hp int) float64 {
+	if hp <= 0 {
+		return 0
+	}
+	return math.Round(float64(damage)*1000/float64(hp)) / 10
+}
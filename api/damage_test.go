@@ -0,0 +1,84 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	"pokeproject/typeeffectiveness"
+)
+
+// withAbilities sets the regular abilities of a Pokemon document.
+func withAbilities(doc map[string]interface{}, abilities ...string) map[string]interface{} {
+	var list []interface{}
+	for i, a := range abilities {
+		list = append(list, map[string]interface{}{"slot": i + 1, "is_hidden": false, "ability": map[string]interface{}{"name": a}})
+	}
+	doc["abilities"] = list
+	return doc
+}
+
+func TestCalculateDamageModifiers(t *testing.T) {
+	game := gameOf(t)
+	cache := testCache(t, rawData{
+		Pokemon: []map[string]interface{}{
+			testPokemon(game, 4, "charmander", []string{"fire"}),
+			withAbilities(testPokemon(game, 143, "snorlax", []string{"normal"}), "immunity", "thick-fat"),
+			withAbilities(testPokemon(game, 437, "bronzong", []string{"steel", "psychic"}), "levitate", "heatproof"),
+		},
+		Moves: []map[string]interface{}{
+			testMove(52, "ember", "fire", "special", 40),
+			testMove(53, "flamethrower", "fire", "special", 95),
+		},
+	})
+	chart, err := typeeffectiveness.ForGeneration(game.Generation)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Level 50 stats are all 70 and Charmander's Fire moves get STAB. The
+	// rolls differ from applying Thick Fat or Heatproof as a final 0.5
+	tests := []struct {
+		name     string
+		attacker BattlerRequest
+		defender BattlerRequest
+		move     string
+		want     []int
+	}{
+		{
+			// Thick Fat halves the attacking stat
+			name:     "thick-fat",
+			attacker: BattlerRequest{Pokemon: "charmander"},
+			defender: BattlerRequest{Pokemon: "snorlax", Ability: "thick-fat"},
+			move:     "ember",
+			want:     []int{12, 12, 12, 12, 12, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 15},
+		},
+		{
+			// Heatproof halves the power of a super effective hit
+			name:     "heatproof",
+			attacker: BattlerRequest{Pokemon: "charmander"},
+			defender: BattlerRequest{Pokemon: "bronzong", Ability: "heatproof"},
+			move:     "flamethrower",
+			want:     []int{54, 54, 56, 56, 56, 56, 60, 60, 60, 60, 60, 62, 62, 62, 62, 66},
+		},
+		{
+			name:     "life-orb",
+			attacker: BattlerRequest{Pokemon: "charmander", Item: "life-orb"},
+			defender: BattlerRequest{Pokemon: "snorlax", Ability: "immunity"},
+			move:     "ember",
+			want:     []int{30, 30, 30, 31, 31, 31, 31, 33, 33, 33, 33, 34, 34, 34, 34, 36},
+		},
+	}
+	for _, tt := range tests {
+		resp, err := calculateDamage(DamageRequest{Attacker: tt.attacker, Defender: tt.defender, Move: tt.move}, cache, chart, "en")
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(resp.Rolls, tt.want) {
+			t.Errorf("%s: got rolls %v, want %v", tt.name, resp.Rolls, tt.want)
+		}
+		if resp.Attacker.Defense != 0 || resp.Defender.Attack != 0 {
+			t.Errorf("%s: got attacker defense %d and defender attack %d, want them left out", tt.name, resp.Attacker.Defense, resp.Defender.Attack)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// EvolutionTrigger is one way to evolve into a species, with the names of
+// the resources it needs. Only the conditions that apply are set.
+type EvolutionTrigger struct {
+	Trigger               string `json:"trigger"`
+	MinLevel              *int   `json:"min_level,omitempty"`
+	Item                  string `json:"item,omitempty"`
+	HeldItem              string `json:"held_item,omitempty"`
+	KnownMove             string `json:"known_move,omitempty"`
+	KnownMoveType         string `json:"known_move_type,omitempty"`
+	Location              string `json:"location,omitempty"`
+	PartySpecies          string `json:"party_species,omitempty"`
+	PartyType             string `json:"party_type,omitempty"`
+	TradeSpecies          string `json:"trade_species,omitempty"`
+	MinHappiness          *int   `json:"min_happiness,omitempty"`
+	MinBeauty             *int   `json:"min_beauty,omitempty"`
+	MinAffection          *int   `json:"min_affection,omitempty"`
+	Gender                *int   `json:"gender,omitempty"`
+	RelativePhysicalStats *int   `json:"relative_physical_stats,omitempty"`
+	TimeOfDay             string `json:"time_of_day,omitempty"`
+	NeedsOverworldRain    bool   `json:"needs_overworld_rain,omitempty"`
+	TurnUpsideDown        bool   `json:"turn_upside_down,omitempty"`
+}
+
+// EvolutionNode is a species of an evolution family. Pokemon is empty when
+// the species is not part of the game's regional Pokédex.
+type EvolutionNode struct {
+	Species   string             `json:"species"`
+	Pokemon   string             `json:"pokemon,omitempty"`
+	IsBaby    bool               `json:"is_baby"`
+	Triggers  []EvolutionTrigger `json:"triggers"`
+	EvolvesTo []EvolutionNode    `json:"evolves_to"`
+}
+
+// PokemonEvolutionsResponse is the API response for a Pokemon's evolution family.
+type PokemonEvolutionsResponse struct {
+	Pokemon     string        `json:"pokemon"`
+	Species     string        `json:"species"`
+	FamilyID    int           `json:"family_id"`
+	EvolvesFrom string        `json:"evolves_from,omitempty"`
+	EvolvesTo   []string      `json:"evolves_to"`
+	Chain       EvolutionNode `json:"chain"`
+}
+
+// GetPokemonEvolutionsCached handles GET /api/pokemon/{name}/evolutions: the
+// whole evolution family of a Pokemon and how each stage is reached.
+func GetPokemonEvolutionsCached(w http.ResponseWriter, r *http.Request, cache *Cache) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/pokemon/")
+	name := strings.ToLower(strings.TrimSuffix(path, "/evolutions"))
+
+	if name == "" {
+		http.Error(w, `{"error": "Pokemon name is required"}`, http.StatusBadRequest)
+		return
+	}
+
+	pokemon := cache.lookupPokemon(name)
+	if pokemon == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Pokemon not found: %s", name)})
+		return
+	}
+
+	chain := cache.EvolutionChain(pokemon)
+	if chain == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("No evolution data for %s", pokemon.Name)})
+		return
+	}
+
+	resp := PokemonEvolutionsResponse{
+		Pokemon:   pokemon.Name,
+		Species:   pokemon.Species.Name,
+		FamilyID:  chain.ID,
+		EvolvesTo: []string{},
+		Chain:     buildEvolutionNode(&chain.Chain, cache),
+	}
+	if resp.Species == "" {
+		resp.Species = pokemon.Name
+	}
+	if link, parent := findChainLink(&chain.Chain, nil, resp.Species); link != nil {
+		if parent != nil {
+			resp.EvolvesFrom = parent.Species.Name
+		}
+		for _, next := range link.EvolvesTo {
+			resp.EvolvesTo = append(resp.EvolvesTo, next.Species.Name)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// buildEvolutionNode converts a chain link and its evolutions for the API.
+func buildEvolutionNode(link *ChainLink, cache *Cache) EvolutionNode {
+	node := EvolutionNode{
+		Species:   link.Species.Name,
+		IsBaby:    link.IsBaby,
+		Triggers:  []EvolutionTrigger{},
+		EvolvesTo: []EvolutionNode{},
+	}
+	if forms := cache.PokemonBySpecies(link.Species.Name); len(forms) > 0 {
+		node.Pokemon = forms[0].Name
+	}
+	for _, d := range link.EvolutionDetails {
+		node.Triggers = append(node.Triggers, buildEvolutionTrigger(d))
+	}
+	for i := range link.EvolvesTo {
+		node.EvolvesTo = append(node.EvolvesTo, buildEvolutionNode(&link.EvolvesTo[i], cache))
+	}
+	return node
+}
+
+// buildEvolutionTrigger flattens an evolution detail to resource names.
+func buildEvolutionTrigger(d EvolutionDetail) EvolutionTrigger {
+	name := func(r *NamedResource) string {
+		if r == nil {
+			return ""
+		}
+		return r.Name
+	}
+	return EvolutionTrigger{
+		Trigger:               d.Trigger.Name,
+		MinLevel:              d.MinLevel,
+		Item:                  name(d.Item),
+		HeldItem:              name(d.HeldItem),
+		KnownMove:             name(d.KnownMove),
+		KnownMoveType:         name(d.KnownMoveType),
+		Location:              name(d.Location),
+		PartySpecies:          name(d.PartySpecies),
+		PartyType:             name(d.PartyType),
+		TradeSpecies:          name(d.TradeSpecies),
+		MinHappiness:          d.MinHappiness,
+		MinBeauty:             d.MinBeauty,
+		MinAffection:          d.MinAffection,
+		Gender:                d.Gender,
+		RelativePhysicalStats: d.RelativePhysicalStats,
+		TimeOfDay:             d.TimeOfDay,
+		NeedsOverworldRain:    d.NeedsOverworldRain,
+		TurnUpsideDown:        d.TurnUpsideDown,
+	}
+}
+
+// findChainLink finds the link of a species in a chain, and its parent link.
+func findChainLink(link, parent *ChainLink, species string) (found, foundParent *ChainLink) {
+	if link.Species.Name == species {
+		return link, parent
+	}
+	for i := range link.EvolvesTo {
+		if found, foundParent := findChainLink(&link.EvolvesTo[i], link, species); found != nil {
+			return found, foundParent
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"testing"
+
+	"pokeproject/games"
+)
+
+// testPokemon builds a Pokemon document with base stats of 50 that learns
+// the given moves in the game's version group.
+func testPokemon(game games.Game, id int, name string, types []string, moves ...LearnedMove) map[string]interface{} {
+	var typeList, stats, moveList []interface{}
+	for i, t := range types {
+		typeList = append(typeList, map[string]interface{}{"slot": i + 1, "type": map[string]interface{}{"name": t}})
+	}
+	for _, s := range standardStats {
+		stats = append(stats, map[string]interface{}{"base_stat": 50, "stat": map[string]interface{}{"name": s}})
+	}
+	// Like PokeAPI, every learn method of a move is a detail of one entry
+	entries := make(map[string]map[string]interface{})
+	for _, m := range moves {
+		entry, ok := entries[m.Name]
+		if !ok {
+			entry = map[string]interface{}{"move": map[string]interface{}{"name": m.Name}}
+			entries[m.Name] = entry
+			moveList = append(moveList, entry)
+		}
+		details, _ := entry["version_group_details"].([]interface{})
+		entry["version_group_details"] = append(details, map[string]interface{}{
+			"level_learned_at":  m.Level,
+			"move_learn_method": map[string]interface{}{"name": m.Method},
+			"version_group":     map[string]interface{}{"name": game.VersionGroup},
+		})
+	}
+	return map[string]interface{}{
+		"id":          id,
+		"name":        name,
+		"regional_id": id,
+		"types":       typeList,
+		"stats":       stats,
+		"moves":       moveList,
+	}
+}
+
+// testMove builds a move document.
+func testMove(id int, name, moveType, class string, power int) map[string]interface{} {
+	return map[string]interface{}{
+		"id":           id,
+		"name":         name,
+		"type":         map[string]interface{}{"name": moveType},
+		"damage_class": map[string]interface{}{"name": class},
+		"power":        power,
+		"pp":           10,
+	}
+}
+
+// testEncounter builds an encounter document of a Pokemon walking in an area
+// in both versions of the game.
+func testEncounter(game games.Game, area, pokemon string) map[string]interface{} {
+	var versions []interface{}
+	for _, v := range game.Versions {
+		versions = append(versions, map[string]interface{}{
+			"max_chance": 10,
+			"version":    map[string]interface{}{"name": v},
+			"encounter_details": []interface{}{map[string]interface{}{
+				"chance":    10,
+				"min_level": 5,
+				"max_level": 5,
+				"method":    map[string]interface{}{"name": "walk"},
+			}},
+		})
+	}
+	return map[string]interface{}{
+		"location_area":   map[string]interface{}{"name": area},
+		"pokemon":         pokemon,
+		"version_details": versions,
+	}
+}
+
+// testCache builds the default game's cache from raw documents.
+func testCache(t *testing.T, raw rawData) *Cache {
+	t.Helper()
+	cache, err := newCache(gameOf(t), raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cache
+}
+
+// testStore returns a Store serving the given caches.
+func testStore(caches ...*Cache) *Store {
+	s := &Store{}
+	m := make(map[string]*Cache)
+	for _, c := range caches {
+		m[c.Game.Key] = c
+	}
+	s.current.Store(&m)
+	return s
+}
+
+// gameOf returns the default game.
+func gameOf(t *testing.T) games.Game {
+	t.Helper()
+	game, err := games.Lookup("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return game
+}
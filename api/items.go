@@ -0,0 +1,148 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"pokeproject/damage"
+)
+
+// ItemSummary represents an item in item search results.
+type ItemSummary struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Category    string `json:"category"`
+	Sprite      string `json:"sprite"`
+}
+
+// ItemMachine is the move taught by a TM or HM item.
+type ItemMachine struct {
+	Move        string `json:"move"`
+	DisplayName string `json:"display_name"`
+}
+
+// ItemHolder is a wild Pokemon that may hold an item, with its rarity per version.
+type ItemHolder struct {
+	Pokemon string         `json:"pokemon"`
+	Rarity  map[string]int `json:"rarity"`
+}
+
+// ItemResponse represents the API response for a single item.
+type ItemResponse struct {
+	Name        string            `json:"name"`
+	DisplayName string            `json:"display_name"`
+	Category    string            `json:"category"`
+	Cost        int               `json:"cost"`
+	FlingPower  *int              `json:"fling_power"`
+	Effect      string            `json:"effect"`
+	Sprite      string            `json:"sprite"`
+	Machine     *ItemMachine      `json:"machine,omitempty"`
+	Modifiers   []damage.Modifier `json:"modifiers"`
+	HeldBy      []ItemHolder      `json:"held_by"`
+}
+
+// GetItemByNameCached returns an item by API or translated name, with the
+// move it teaches if it is a TM or HM, its battle modifiers and the wild
+// Pokemon that may hold it in the game's versions.
+func GetItemByNameCached(w http.ResponseWriter, r *http.Request, cache *Cache) {
+	name := strings.TrimPrefix(r.URL.Path, "/api/items/")
+	name = strings.ToLower(strings.TrimSpace(name))
+	lang := getLang(r)
+
+	if name == "" {
+		http.Error(w, `{"error": "Item name is required"}`, http.StatusBadRequest)
+		return
+	}
+
+	item := cache.lookupItem(name)
+	if item == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Item not found: %s", name)})
+		return
+	}
+
+	resp := ItemResponse{
+		Name:        item.Name,
+		DisplayName: item.DisplayName(lang),
+		Category:    item.Category.Name,
+		Cost:        item.Cost,
+		FlingPower:  item.FlingPower,
+		Effect:      item.Effect(lang),
+		Sprite:      item.Sprites.Default,
+		Modifiers:   damage.ItemModifiers(item.Name),
+		HeldBy:      itemHolders(cache, item.Name),
+	}
+	if resp.Modifiers == nil {
+		resp.Modifiers = []damage.Modifier{}
+	}
+	if m := cache.MachineForItem(item.Name); m != nil {
+		resp.Machine = &ItemMachine{Move: m.Move.Name, DisplayName: m.Move.Name}
+		if move, ok := cache.Moves[m.Move.Name]; ok {
+			resp.Machine.DisplayName = move.DisplayName(lang)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// SearchItemsCached handles GET /api/items. ?q= filters by API or translated
+// name and ?category= by item category (e.g. held-items, all-machines).
+func SearchItemsCached(w http.ResponseWriter, r *http.Request, cache *Cache) {
+	query := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("q")))
+	category := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("category")))
+	lang := getLang(r)
+
+	matches := make(map[string]bool)
+	for translated, apiName := range cache.itemNames {
+		if strings.Contains(translated, query) {
+			matches[apiName] = true
+		}
+	}
+
+	results := []ItemSummary{}
+	for name := range matches {
+		item := cache.Items[name]
+		if category != "" && item.Category.Name != category {
+			continue
+		}
+		results = append(results, ItemSummary{
+			Name:        item.Name,
+			DisplayName: item.DisplayName(lang),
+			Category:    item.Category.Name,
+			Sprite:      item.Sprites.Default,
+		})
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+// itemHolders lists the Pokemon that may hold an item in the game's versions.
+func itemHolders(cache *Cache, itemName string) []ItemHolder {
+	holders := []ItemHolder{}
+	for _, p := range cache.Pokemon {
+		for _, held := range p.HeldItems {
+			if held.Item.Name != itemName {
+				continue
+			}
+			rarity := make(map[string]int)
+			for _, detail := range held.VersionDetails {
+				for _, version := range cache.Game.Versions {
+					if detail.Version.Name == version {
+						rarity[version] = detail.Rarity
+					}
+				}
+			}
+			if len(rarity) > 0 {
+				holders = append(holders, ItemHolder{Pokemon: p.Name, Rarity: rarity})
+			}
+		}
+	}
+	return holders
+}
@@ -0,0 +1,319 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// timeConditionPrefix marks the encounter conditions that set the time of day,
+// e.g. "time-morning".
+const timeConditionPrefix = "time-"
+
+// EncounterEntry is a way to meet a Pokemon in a location area. Slots that
+// only differ in time of day or version are merged: TimeOfDay is empty when
+// the slot does not depend on it, and Exclusive names the only version that
+// has it. A slot found in both versions with different levels or rates gives
+// one entry per version, neither of them exclusive.
+type EncounterEntry struct {
+	Pokemon    string   `json:"pokemon"`
+	Area       string   `json:"area"`
+	Method     string   `json:"method"`
+	MinLevel   int      `json:"min_level"`
+	MaxLevel   int      `json:"max_level"`
+	TimeOfDay  []string `json:"time_of_day,omitempty"`
+	Conditions []string `json:"conditions,omitempty"`
+	Rate       int      `json:"rate"`
+	Versions   []string `json:"versions"`
+	Exclusive  string   `json:"exclusive,omitempty"`
+}
+
+// PokemonLocationsResponse is the API response for where a Pokemon is found.
+// ExclusiveTo is set when it is only found in the wild in one version.
+type PokemonLocationsResponse struct {
+	Pokemon     string           `json:"pokemon"`
+	ExclusiveTo string           `json:"exclusive_to,omitempty"`
+	Encounters  []EncounterEntry `json:"encounters"`
+}
+
+// LocationAreaResponse is the API response for the Pokemon of a location area.
+type LocationAreaResponse struct {
+	Area       string           `json:"area"`
+	Encounters []EncounterEntry `json:"encounters"`
+}
+
+// GetPokemonLocationsCached handles GET /api/pokemon/{name}/locations: the
+// wild encounters of a Pokemon in the selected game, optionally limited to
+// one of its versions with ?version=.
+func GetPokemonLocationsCached(w http.ResponseWriter, r *http.Request, cache *Cache) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/pokemon/")
+	name := strings.ToLower(strings.TrimSuffix(path, "/locations"))
+
+	if name == "" {
+		http.Error(w, `{"error": "Pokemon name is required"}`, http.StatusBadRequest)
+		return
+	}
+
+	pokemon := cache.lookupPokemon(name)
+	if pokemon == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Pokemon not found: %s", name)})
+		return
+	}
+
+	version, err := encounterVersion(r, cache)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	entries := encounterEntries(cache.EncountersOf(pokemon), cache)
+	resp := PokemonLocationsResponse{
+		Pokemon:     pokemon.Name,
+		ExclusiveTo: exclusiveVersion(entries, cache),
+		Encounters:  filterEncounterVersion(entries, version),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// GetLocationAreaCached handles GET /api/locations/{area}: every Pokemon found
+// in a location area, optionally limited to one version with ?version=. The
+// "-area" suffix of PokeAPI area names may be left out.
+func GetLocationAreaCached(w http.ResponseWriter, r *http.Request, cache *Cache) {
+	area := strings.ToLower(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/locations/"), "/"))
+	if area == "" {
+		http.Error(w, `{"error": "Location area is required"}`, http.StatusBadRequest)
+		return
+	}
+
+	encounters := cache.AreaEncounters(area)
+	if encounters == nil {
+		area += "-area"
+		encounters = cache.AreaEncounters(area)
+	}
+	if encounters == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Location area not found: %s", strings.TrimSuffix(area, "-area"))})
+		return
+	}
+
+	version, err := encounterVersion(r, cache)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	resp := LocationAreaResponse{
+		Area:       area,
+		Encounters: filterEncounterVersion(encounterEntries(encounters, cache), version),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// encounterVersion returns the version requested with ?version=, which must
+// be one of the game's, or "" for all of them.
+func encounterVersion(r *http.Request, cache *Cache) (string, error) {
+	version := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("version")))
+	if version == "" {
+		return "", nil
+	}
+	for _, v := range cache.Game.Versions {
+		if v == version {
+			return version, nil
+		}
+	}
+	return "", fmt.Errorf("unknown version %q for %s (valid: %s)", version, cache.Game.Name, strings.Join(cache.Game.Versions, ", "))
+}
+
+// encounterSlot is the key of an encounter entry while slots are merged.
+type encounterSlot struct {
+	pokemon, area, method string
+	conditions, times     string
+	minLevel, maxLevel    int
+	rate                  int
+	versions              string
+}
+
+// encounterEntries turns raw encounters into entries. Slots of the same
+// method and conditions are added up per version, then versions with the
+// same levels and rate are merged, and finally times of day.
+func encounterEntries(encounters []*Encounter, cache *Cache) []EncounterEntry {
+	// Levels and rate per version, time of day and other conditions
+	var slots []encounterSlot
+	index := make(map[encounterSlot]int)
+	// Versions that have each slot, whatever its levels and rate
+	present := make(map[encounterSlot]map[string]bool)
+	for _, enc := range encounters {
+		for _, vd := range enc.VersionDetails {
+			for _, d := range vd.EncounterDetails {
+				var times, others []string
+				for _, c := range d.ConditionValues {
+					if strings.HasPrefix(c.Name, timeConditionPrefix) {
+						times = append(times, strings.TrimPrefix(c.Name, timeConditionPrefix))
+					} else {
+						others = append(others, c.Name)
+					}
+				}
+				key := encounterSlot{
+					pokemon:    enc.Pokemon,
+					area:       enc.LocationArea.Name,
+					method:     d.Method.Name,
+					conditions: strings.Join(others, ","),
+					times:      strings.Join(times, ","),
+				}
+				if present[key] == nil {
+					present[key] = make(map[string]bool)
+				}
+				present[key][vd.Version.Name] = true
+
+				key.versions = vd.Version.Name
+				i, ok := index[key]
+				if !ok {
+					i = len(slots)
+					index[key] = i
+					slot := key
+					slot.minLevel, slot.maxLevel = d.MinLevel, d.MaxLevel
+					slots = append(slots, slot)
+				}
+				slots[i].minLevel = min(slots[i].minLevel, d.MinLevel)
+				slots[i].maxLevel = max(slots[i].maxLevel, d.MaxLevel)
+				slots[i].rate += d.Chance
+			}
+		}
+	}
+
+	// Merge versions with the same slot, then times of day
+	slots = mergeSlots(slots, func(s encounterSlot) (encounterSlot, string) {
+		v := s.versions
+		s.versions = ""
+		return s, v
+	}, func(s *encounterSlot, v string) { s.versions = v })
+	slots = mergeSlots(slots, func(s encounterSlot) (encounterSlot, string) {
+		t := s.times
+		s.times = ""
+		return s, t
+	}, func(s *encounterSlot, t string) { s.times = t })
+
+	entries := make([]EncounterEntry, 0, len(slots))
+	for _, s := range slots {
+		entry := EncounterEntry{
+			Pokemon:    s.pokemon,
+			Area:       s.area,
+			Method:     s.method,
+			MinLevel:   s.minLevel,
+			MaxLevel:   s.maxLevel,
+			TimeOfDay:  splitList(s.times),
+			Conditions: splitList(s.conditions),
+			Rate:       s.rate,
+			Versions:   splitList(s.versions),
+		}
+		if len(entry.Versions) == 1 && len(cache.Game.Versions) > 1 && onlyInVersion(present, s, entry.Versions[0]) {
+			entry.Exclusive = entry.Versions[0]
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// onlyInVersion reports whether no other version has the slot at any of its
+// times of day. Levels and rates are ignored, so a slot found in both
+// versions with different rates is not exclusive.
+func onlyInVersion(present map[encounterSlot]map[string]bool, s encounterSlot, version string) bool {
+	times := splitList(s.times)
+	if times == nil {
+		times = []string{""}
+	}
+	for _, t := range times {
+		key := encounterSlot{pokemon: s.pokemon, area: s.area, method: s.method, conditions: s.conditions, times: t}
+		for v := range present[key] {
+			if v != version {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// mergeSlots merges slots that are equal once split removes one field,
+// joining the removed values in order with join. The first slot of each
+// group keeps its position.
+func mergeSlots(slots []encounterSlot, split func(encounterSlot) (encounterSlot, string), join func(*encounterSlot, string)) []encounterSlot {
+	var merged []encounterSlot
+	var values [][]string
+	index := make(map[encounterSlot]int)
+	for _, s := range slots {
+		key, value := split(s)
+		i, ok := index[key]
+		if !ok {
+			i = len(merged)
+			index[key] = i
+			merged = append(merged, key)
+			values = append(values, nil)
+		}
+		if value != "" {
+			values[i] = append(values[i], value)
+		}
+	}
+	for i := range merged {
+		join(&merged[i], strings.Join(values[i], ","))
+	}
+	return merged
+}
+
+// splitList splits a comma-separated list, returning nil for "".
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// exclusiveVersion returns the only version of a multi-version game in which
+// the entries are found, or "".
+func exclusiveVersion(entries []EncounterEntry, cache *Cache) string {
+	if len(cache.Game.Versions) < 2 {
+		return ""
+	}
+	found := make(map[string]bool)
+	for _, e := range entries {
+		for _, v := range e.Versions {
+			found[v] = true
+		}
+	}
+	if len(found) != 1 {
+		return ""
+	}
+	for v := range found {
+		return v
+	}
+	return ""
+}
+
+// filterEncounterVersion keeps the entries found in a version, or every entry
+// if version is "".
+func filterEncounterVersion(entries []EncounterEntry, version string) []EncounterEntry {
+	if version == "" {
+		return entries
+	}
+	filtered := []EncounterEntry{}
+	for _, e := range entries {
+		for _, v := range e.Versions {
+			if v == version {
+				filtered = append(filtered, e)
+				break
+			}
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+// walk returns a walking encounter slot at level 5 with a chance and
+// conditions.
+func walk(chance int, conditions ...string) EncounterDetail {
+	d := EncounterDetail{MinLevel: 5, MaxLevel: 5, Chance: chance, Method: NamedResource{Name: "walk"}}
+	for _, c := range conditions {
+		d.ConditionValues = append(d.ConditionValues, NamedResource{Name: c})
+	}
+	return d
+}
+
+func TestEncounterEntriesExclusive(t *testing.T) {
+	cache := testCache(t, rawData{})
+	encounter := func(area string, heartgold, soulsilver []EncounterDetail) *Encounter {
+		enc := &Encounter{Pokemon: "wooper", LocationArea: NamedResource{Name: area}}
+		if heartgold != nil {
+			enc.VersionDetails = append(enc.VersionDetails, EncounterVersionDetails{Version: NamedResource{Name: "heartgold"}, EncounterDetails: heartgold})
+		}
+		if soulsilver != nil {
+			enc.VersionDetails = append(enc.VersionDetails, EncounterVersionDetails{Version: NamedResource{Name: "soulsilver"}, EncounterDetails: soulsilver})
+		}
+		return enc
+	}
+
+	type entry struct {
+		Times     []string
+		Rate      int
+		Versions  []string
+		Exclusive string
+	}
+	tests := []struct {
+		name string
+		enc  *Encounter
+		want []entry
+	}{
+		{
+			name: "same rate",
+			enc:  encounter("route-32-area", []EncounterDetail{walk(10)}, []EncounterDetail{walk(10)}),
+			want: []entry{{Rate: 10, Versions: []string{"heartgold", "soulsilver"}}},
+		},
+		{
+			name: "heartgold only",
+			enc:  encounter("route-32-area", []EncounterDetail{walk(10)}, nil),
+			want: []entry{{Rate: 10, Versions: []string{"heartgold"}, Exclusive: "heartgold"}},
+		},
+		{
+			// Found in both versions, so neither entry is exclusive
+			name: "different rates",
+			enc:  encounter("route-32-area", []EncounterDetail{walk(10)}, []EncounterDetail{walk(20)}),
+			want: []entry{
+				{Rate: 10, Versions: []string{"heartgold"}},
+				{Rate: 20, Versions: []string{"soulsilver"}},
+			},
+		},
+		{
+			name: "different rates at one time",
+			enc: encounter("route-32-area",
+				[]EncounterDetail{walk(10, "time-morning"), walk(10, "time-day"), walk(30, "time-night")},
+				[]EncounterDetail{walk(10, "time-morning"), walk(10, "time-day"), walk(20, "time-night")}),
+			want: []entry{
+				{Times: []string{"morning", "day"}, Rate: 10, Versions: []string{"heartgold", "soulsilver"}},
+				{Times: []string{"night"}, Rate: 30, Versions: []string{"heartgold"}},
+				{Times: []string{"night"}, Rate: 20, Versions: []string{"soulsilver"}},
+			},
+		},
+		{
+			// Soul Silver has no night slot
+			name: "missing time",
+			enc: encounter("route-32-area",
+				[]EncounterDetail{walk(10, "time-morning"), walk(10, "time-night")},
+				[]EncounterDetail{walk(10, "time-morning")}),
+			want: []entry{
+				{Times: []string{"morning"}, Rate: 10, Versions: []string{"heartgold", "soulsilver"}},
+				{Times: []string{"night"}, Rate: 10, Versions: []string{"heartgold"}, Exclusive: "heartgold"},
+			},
+		},
+		{
+			// Heart Gold's night slot has Soul Silver's day rate
+			name: "rate of another time",
+			enc: encounter("route-32-area",
+				[]EncounterDetail{walk(10, "time-day"), walk(20, "time-night")},
+				[]EncounterDetail{walk(20, "time-day")}),
+			want: []entry{
+				{Times: []string{"day"}, Rate: 10, Versions: []string{"heartgold"}},
+				{Times: []string{"night"}, Rate: 20, Versions: []string{"heartgold"}, Exclusive: "heartgold"},
+				{Times: []string{"day"}, Rate: 20, Versions: []string{"soulsilver"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		var got []entry
+		for _, e := range encounterEntries([]*Encounter{tt.enc}, cache) {
+			got = append(got, entry{Times: e.TimeOfDay, Rate: e.Rate, Versions: e.Versions, Exclusive: e.Exclusive})
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+)
+
+// MachineEntry is a TM or HM with the move it teaches and the Pokemon of the
+// game that can learn it.
+type MachineEntry struct {
+	Item        string   `json:"item"`
+	Kind        string   `json:"kind"`
+	Number      int      `json:"number"`
+	Move        string   `json:"move"`
+	DisplayName string   `json:"display_name"`
+	Type        string   `json:"type"`
+	DamageClass string   `json:"damage_class"`
+	Pokemon     []string `json:"pokemon"`
+}
+
+// GetMachinesCached handles GET /api/machines: every TM and HM of the game,
+// TMs first, with the Pokemon of the regional Pokédex that learn each one.
+func GetMachinesCached(w http.ResponseWriter, r *http.Request, cache *Cache) {
+	lang := getLang(r)
+
+	machines := []MachineEntry{}
+	for _, m := range cache.Machines {
+		kind, number, ok := m.Number()
+		if !ok {
+			continue
+		}
+		entry := MachineEntry{
+			Item:        m.Item.Name,
+			Kind:        kind,
+			Number:      number,
+			Move:        m.Move.Name,
+			DisplayName: m.Move.Name,
+			Pokemon:     []string{},
+		}
+		if move, ok := cache.Moves[m.Move.Name]; ok {
+			entry.DisplayName = move.DisplayName(lang)
+			entry.Type = move.Type.Name
+			entry.DamageClass = move.DamageClass.Name
+		}
+		for _, p := range cache.MoveLearners(m.Move.Name) {
+			if p.LearnsMoveBy(cache.Game.VersionGroup, m.Move.Name, "machine") {
+				entry.Pokemon = append(entry.Pokemon, p.Name)
+			}
+		}
+		machines = append(machines, entry)
+	}
+
+	sort.Slice(machines, func(i, j int) bool {
+		if machines[i].Kind != machines[j].Kind {
+			return machines[i].Kind == "tm"
+		}
+		return machines[i].Number < machines[j].Number
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(machines)
+}
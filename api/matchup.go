@@ -0,0 +1,232 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"pokeproject/typeeffectiveness"
+)
+
+const (
+	// stabMultiplier boosts moves matching one of the user's types.
+	stabMultiplier = 1.5
+	// minIncomingFactor keeps immunities from making a matchup score infinite.
+	minIncomingFactor = 0.25
+)
+
+// MatchupHit is a super effective move of one side against a Pokemon of the
+// other.
+type MatchupHit struct {
+	Pokemon       string  `json:"pokemon"`
+	Move          string  `json:"move"`
+	DisplayName   string  `json:"display_name"`
+	Effectiveness float64 `json:"effectiveness"`
+}
+
+// MatchupScore rates one of our members against an opposing Pokemon. Offense
+// is the best multiplier of its damaging moves, STAB included; Defense is
+// the worst multiplier it takes from the opponent's damaging moves.
+type MatchupScore struct {
+	Pokemon string  `json:"pokemon"`
+	Offense float64 `json:"offense"`
+	Defense float64 `json:"defense"`
+	Score   float64 `json:"score"`
+}
+
+// OpponentMatchup is the report for one Pokemon of the trainer's team.
+// Counters are our members' super effective moves against it and Threats
+// are its super effective moves against our members, strongest first.
+type OpponentMatchup struct {
+	Pokemon    string         `json:"pokemon"`
+	Level      int            `json:"level"`
+	Types      []string       `json:"types"`
+	Ability    string         `json:"ability,omitempty"`
+	Counters   []MatchupHit   `json:"counters"`
+	Threats    []MatchupHit   `json:"threats"`
+	Scores     []MatchupScore `json:"scores"`
+	BestAnswer string         `json:"best_answer,omitempty"`
+}
+
+// MatchupResponse is the API response for a team against a trainer.
+// SuggestedLead is the member that fares best against the trainer's first
+// Pokemon, with ties broken by how it fares against the whole team.
+type MatchupResponse struct {
+	Trainer       TrainerSummary    `json:"trainer"`
+	Opponents     []OpponentMatchup `json:"opponents"`
+	SuggestedLead string            `json:"suggested_lead,omitempty"`
+}
+
+// TrainerMatchupCached handles POST /api/trainers/{id}/matchup: how a team,
+// given in the same shape as POST /api/team/analyze, fares against each
+// Pokemon of a trainer. The type chart is selected with ?gen= and, as for a
+// team analysis, ?run= restricts the team to a Nuzlocke run's living Pokemon.
+func TrainerMatchupCached(w http.ResponseWriter, r *http.Request, cache *Cache, run *Run) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/trainers/")
+	id := strings.ToLower(strings.TrimSuffix(path, "/matchup"))
+
+	trainer := cache.Trainer(id)
+	if trainer == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Trainer not found: %s", id)})
+		return
+	}
+
+	var req TeamAnalysisRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	chart, err := getChart(r, cache.Game.Generation)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	team, err := runTeam(req.Team, run, cache)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	lang := getLang(r)
+	members, err := resolveTeam(team, cache, lang)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	opponents := trainerMembers(*trainer, cache, lang)
+	resp := MatchupResponse{
+		Trainer:   buildTrainerSummary(*trainer),
+		Opponents: make([]OpponentMatchup, 0, len(opponents)),
+	}
+	for i, opp := range opponents {
+		resp.Opponents = append(resp.Opponents, buildOpponentMatchup(chart, members, opp, trainer.Team[i].Level))
+	}
+	resp.SuggestedLead = suggestLead(resp.Opponents)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// buildOpponentMatchup compares every member of our team with one opposing
+// Pokemon.
+func buildOpponentMatchup(chart *typeeffectiveness.Chart, members []analyzedMember, opp analyzedMember, level int) OpponentMatchup {
+	m := OpponentMatchup{
+		Pokemon:  opp.Name,
+		Level:    level,
+		Types:    opp.Types,
+		Ability:  opp.Ability,
+		Counters: []MatchupHit{},
+		Threats:  []MatchupHit{},
+		Scores:   make([]MatchupScore, 0, len(members)),
+	}
+
+	best := -1.0
+	for _, member := range members {
+		offense, hits := bestHits(chart, member, opp)
+		defense, threats := bestHits(chart, opp, member)
+		m.Counters = append(m.Counters, hits...)
+		m.Threats = append(m.Threats, threats...)
+
+		score := MatchupScore{
+			Pokemon: member.Name,
+			Offense: offense,
+			Defense: defense,
+			Score:   offense / max(defense, minIncomingFactor),
+		}
+		m.Scores = append(m.Scores, score)
+		if score.Score > best {
+			best = score.Score
+			m.BestAnswer = member.Name
+		}
+	}
+
+	sortHits(m.Counters)
+	sortHits(m.Threats)
+	return m
+}
+
+// bestHits returns the best multiplier of the attacker's damaging moves
+// against the defender, counting STAB, and the moves that are super
+// effective. An attacker without damaging moves scores 0.
+func bestHits(chart *typeeffectiveness.Chart, attacker, defender analyzedMember) (float64, []MatchupHit) {
+	best := 0.0
+	var hits []MatchupHit
+	for _, move := range attacker.Moves {
+		if move.DamageClass != "physical" && move.DamageClass != "special" {
+			continue
+		}
+		factor := chart.GetEffectivenessWithAbility(move.Type, defender.Types, defender.Ability)
+		power := factor
+		for _, t := range attacker.Types {
+			if t == move.Type {
+				power *= stabMultiplier
+				break
+			}
+		}
+		best = max(best, power)
+		if factor > 1.0 {
+			hits = append(hits, MatchupHit{
+				Pokemon:       attacker.Name,
+				Move:          move.Name,
+				DisplayName:   move.DisplayName,
+				Effectiveness: factor,
+			})
+		}
+	}
+	return best, hits
+}
+
+// sortHits orders hits by effectiveness, strongest first, keeping team order
+// for ties.
+func sortHits(hits []MatchupHit) {
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Effectiveness > hits[j].Effectiveness
+	})
+}
+
+// suggestLead picks the member with the best score against the first
+// opposing Pokemon; ties go to the best total score against the whole team,
+// then to team order.
+func suggestLead(opponents []OpponentMatchup) string {
+	if len(opponents) == 0 {
+		return ""
+	}
+	// Scores are in team order for every opponent
+	totals := make([]float64, len(opponents[0].Scores))
+	for _, opp := range opponents {
+		for i, s := range opp.Scores {
+			totals[i] += s.Score
+		}
+	}
+
+	best := -1
+	for i, s := range opponents[0].Scores {
+		if best < 0 {
+			best = i
+			continue
+		}
+		leader := opponents[0].Scores[best]
+		if s.Score > leader.Score || (s.Score == leader.Score && totals[i] > totals[best]) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return ""
+	}
+	return opponents[0].Scores[best].Pokemon
+}
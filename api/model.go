@@ -0,0 +1,702 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"pokeproject/stats"
+	"pokeproject/typeeffectiveness"
+)
+
+// standardStats lists the six stats every Pokemon must have, in PokeAPI order.
+var standardStats = []string{"hp", "attack", "defense", "special-attack", "special-defense", "speed"}
+
+// NamedResource is a PokeAPI reference to another resource.
+type NamedResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+// LocalizedName is a resource name in a given language.
+type LocalizedName struct {
+	Name     string        `json:"name"`
+	Language NamedResource `json:"language"`
+}
+
+// EffectEntry is an effect description in a given language.
+type EffectEntry struct {
+	Effect      string        `json:"effect"`
+	ShortEffect string        `json:"short_effect,omitempty"`
+	Language    NamedResource `json:"language"`
+}
+
+// PokemonType is one of the (up to two) type slots of a Pokemon.
+type PokemonType struct {
+	Slot int           `json:"slot"`
+	Type NamedResource `json:"type"`
+}
+
+// PastPokemonTypes are the types a Pokemon had up to and including a
+// generation, before they changed (e.g. Clefairy was Normal until Fairy was
+// added in Generation VI).
+type PastPokemonTypes struct {
+	Generation NamedResource `json:"generation"`
+	Types      []PokemonType `json:"types"`
+}
+
+// PokemonAbility is one of the ability slots of a Pokemon.
+type PokemonAbility struct {
+	IsHidden bool          `json:"is_hidden"`
+	Slot     int           `json:"slot"`
+	Ability  NamedResource `json:"ability"`
+}
+
+// PastPokemonAbilities are the abilities a Pokemon had in some of its slots
+// up to and including a generation. A slot without an ability did not exist
+// yet.
+type PastPokemonAbilities struct {
+	Generation NamedResource    `json:"generation"`
+	Abilities  []PokemonAbility `json:"abilities"`
+}
+
+// Stat is a base stat of a Pokemon.
+type Stat struct {
+	BaseStat int           `json:"base_stat"`
+	Effort   int           `json:"effort"`
+	Stat     NamedResource `json:"stat"`
+}
+
+// Sprites holds the sprite URLs of a Pokemon.
+type Sprites struct {
+	FrontDefault     string `json:"front_default"`
+	FrontFemale      string `json:"front_female,omitempty"`
+	FrontShiny       string `json:"front_shiny,omitempty"`
+	FrontShinyFemale string `json:"front_shiny_female,omitempty"`
+	BackDefault      string `json:"back_default"`
+	BackFemale       string `json:"back_female,omitempty"`
+	BackShiny        string `json:"back_shiny,omitempty"`
+	BackShinyFemale  string `json:"back_shiny_female,omitempty"`
+}
+
+// LearnDetail describes how a move is learned in a version group.
+type LearnDetail struct {
+	LevelLearnedAt  int           `json:"level_learned_at"`
+	MoveLearnMethod NamedResource `json:"move_learn_method"`
+	VersionGroup    NamedResource `json:"version_group"`
+}
+
+// LearnsetEntry is a move a Pokemon can learn, with the details per version group.
+type LearnsetEntry struct {
+	Move                NamedResource `json:"move"`
+	VersionGroupDetails []LearnDetail `json:"version_group_details"`
+}
+
+// Learnset is every move a Pokemon can learn across all version groups.
+type Learnset []LearnsetEntry
+
+// LearnedMove is a move a Pokemon learns in a single version group.
+type LearnedMove struct {
+	Name   string
+	Method string
+	Level  int
+}
+
+// ForVersionGroup returns the moves learnable in a version group. When a move
+// has several learn methods only the first one is returned.
+func (l Learnset) ForVersionGroup(versionGroup string) []LearnedMove {
+	var moves []LearnedMove
+	for _, entry := range l {
+		for _, detail := range entry.VersionGroupDetails {
+			if detail.VersionGroup.Name != versionGroup {
+				continue
+			}
+			moves = append(moves, LearnedMove{
+				Name:   entry.Move.Name,
+				Method: detail.MoveLearnMethod.Name,
+				Level:  detail.LevelLearnedAt,
+			})
+			break
+		}
+	}
+	return moves
+}
+
+// AllForVersionGroup returns every way a move is learned in a version group,
+// one entry per learn detail.
+func (l Learnset) AllForVersionGroup(versionGroup string) []LearnedMove {
+	var moves []LearnedMove
+	for _, entry := range l {
+		for _, detail := range entry.VersionGroupDetails {
+			if detail.VersionGroup.Name == versionGroup {
+				moves = append(moves, LearnedMove{
+					Name:   entry.Move.Name,
+					Method: detail.MoveLearnMethod.Name,
+					Level:  detail.LevelLearnedAt,
+				})
+			}
+		}
+	}
+	return moves
+}
+
+// HeldItem is an item a wild Pokemon may hold, with its rarity per version.
+type HeldItem struct {
+	Item           NamedResource `json:"item"`
+	VersionDetails []struct {
+		Rarity  int           `json:"rarity"`
+		Version NamedResource `json:"version"`
+	} `json:"version_details"`
+}
+
+// Pokemon is a Pokemon as stored in the data files.
+type Pokemon struct {
+	ID                     int                    `json:"id"`
+	Name                   string                 `json:"name"`
+	RegionalID             int                    `json:"regional_id"`
+	BaseExperience         int                    `json:"base_experience"`
+	Height                 int                    `json:"height"`
+	Weight                 int                    `json:"weight"`
+	Species                NamedResource          `json:"species"`
+	Types                  []PokemonType          `json:"types"`
+	PastTypes              []PastPokemonTypes     `json:"past_types,omitempty"`
+	Abilities              []PokemonAbility       `json:"abilities"`
+	PastAbilities          []PastPokemonAbilities `json:"past_abilities,omitempty"`
+	HeldItems              []HeldItem             `json:"held_items"`
+	Stats                  []Stat                 `json:"stats"`
+	Sprites                Sprites                `json:"sprites"`
+	Moves                  Learnset               `json:"moves"`
+	LocationAreaEncounters string                 `json:"location_area_encounters"`
+}
+
+// TypeNames returns the names of the Pokemon's types in slot order.
+func (p *Pokemon) TypeNames() []string {
+	names := make([]string, 0, len(p.Types))
+	for _, t := range p.Types {
+		names = append(names, t.Type.Name)
+	}
+	return names
+}
+
+// HasType reports whether the Pokemon has the given type.
+func (p *Pokemon) HasType(typeName string) bool {
+	for _, t := range p.Types {
+		if t.Type.Name == typeName {
+			return true
+		}
+	}
+	return false
+}
+
+// BaseStat returns the base value of a stat, or 0 if the stat is missing.
+func (p *Pokemon) BaseStat(name string) int {
+	for _, s := range p.Stats {
+		if s.Stat.Name == name {
+			return s.BaseStat
+		}
+	}
+	return 0
+}
+
+// BaseStats returns the Pokemon's six base stats.
+func (p *Pokemon) BaseStats() stats.Stats {
+	var base stats.Stats
+	for _, s := range p.Stats {
+		base.Set(s.Stat.Name, s.BaseStat)
+	}
+	return base
+}
+
+// HasAbility reports whether the Pokemon can have the given ability.
+func (p *Pokemon) HasAbility(abilityName string) bool {
+	for _, a := range p.Abilities {
+		if a.Ability.Name == abilityName {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultAbility returns the Pokemon's first regular (non-hidden) ability,
+// which is the one assumed when an analysis does not name one.
+func (p *Pokemon) DefaultAbility() string {
+	best := ""
+	bestSlot := 0
+	for _, a := range p.Abilities {
+		if a.IsHidden {
+			continue
+		}
+		if best == "" || a.Slot < bestSlot {
+			best, bestSlot = a.Ability.Name, a.Slot
+		}
+	}
+	return best
+}
+
+// LearnsMove reports whether the Pokemon can learn a move in a version group.
+func (p *Pokemon) LearnsMove(versionGroup, moveName string) bool {
+	for _, entry := range p.Moves {
+		if entry.Move.Name != moveName {
+			continue
+		}
+		for _, detail := range entry.VersionGroupDetails {
+			if detail.VersionGroup.Name == versionGroup {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// LearnDetails returns every way the Pokemon learns a move in a version group.
+func (p *Pokemon) LearnDetails(versionGroup, moveName string) []LearnedMove {
+	var details []LearnedMove
+	for _, entry := range p.Moves {
+		if entry.Move.Name != moveName {
+			continue
+		}
+		for _, detail := range entry.VersionGroupDetails {
+			if detail.VersionGroup.Name == versionGroup {
+				details = append(details, LearnedMove{
+					Name:   moveName,
+					Method: detail.MoveLearnMethod.Name,
+					Level:  detail.LevelLearnedAt,
+				})
+			}
+		}
+	}
+	return details
+}
+
+// LearnsMoveBy reports whether the Pokemon can learn a move in a version group
+// with the given learn method (e.g. "machine").
+func (p *Pokemon) LearnsMoveBy(versionGroup, moveName, method string) bool {
+	for _, entry := range p.Moves {
+		if entry.Move.Name != moveName {
+			continue
+		}
+		for _, detail := range entry.VersionGroupDetails {
+			if detail.VersionGroup.Name == versionGroup && detail.MoveLearnMethod.Name == method {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// resolveTypes replaces the Pokemon's current types with the ones it had in
+// the given generation. PokeAPI lists past types by the last generation they
+// were used in, so the earliest entry not older than the generation applies.
+func (p *Pokemon) resolveTypes(generation int) error {
+	best := 0
+	var types []PokemonType
+	for _, past := range p.PastTypes {
+		if past.Generation.Name == "" {
+			return errors.New("past types without generation")
+		}
+		gen, err := typeeffectiveness.ParseGeneration(past.Generation.Name)
+		if err != nil {
+			return fmt.Errorf("past types with invalid generation %q", past.Generation.Name)
+		}
+		if gen >= generation && (best == 0 || gen < best) {
+			best, types = gen, past.Types
+		}
+	}
+	if best > 0 {
+		p.Types = types
+	}
+	return nil
+}
+
+// resolveAbilities replaces the Pokemon's current abilities with the ones it
+// had in the given generation. Like past types, past abilities are listed by
+// the last generation they were used in, but only for the slots that
+// changed, so each slot takes the earliest entry not older than the
+// generation that mentions it. Hidden abilities only exist from Generation V.
+func (p *Pokemon) resolveAbilities(generation int) error {
+	past := make(map[int]PokemonAbility)
+	pastGen := make(map[int]int)
+	for _, entry := range p.PastAbilities {
+		if entry.Generation.Name == "" {
+			return errors.New("past abilities without generation")
+		}
+		gen, err := typeeffectiveness.ParseGeneration(entry.Generation.Name)
+		if err != nil {
+			return fmt.Errorf("past abilities with invalid generation %q", entry.Generation.Name)
+		}
+		if gen < generation {
+			continue
+		}
+		for _, a := range entry.Abilities {
+			if g, ok := pastGen[a.Slot]; !ok || gen < g {
+				past[a.Slot], pastGen[a.Slot] = a, gen
+			}
+		}
+	}
+
+	var abilities []PokemonAbility
+	for _, a := range p.Abilities {
+		if old, ok := past[a.Slot]; ok {
+			a = old
+			delete(past, a.Slot)
+		}
+		if a.Ability.Name == "" || (a.IsHidden && generation < 5) {
+			continue
+		}
+		abilities = append(abilities, a)
+	}
+	// Slots that were removed since
+	for _, a := range past {
+		if a.Ability.Name != "" && !(a.IsHidden && generation < 5) {
+			abilities = append(abilities, a)
+		}
+	}
+	sort.Slice(abilities, func(i, j int) bool { return abilities[i].Slot < abilities[j].Slot })
+	p.Abilities = abilities
+	return nil
+}
+
+// validate checks that the Pokemon has the fields every handler relies on and
+// that its types exist in the game's type chart.
+func (p *Pokemon) validate(chart *typeeffectiveness.Chart) error {
+	if p.Name == "" {
+		return errors.New("missing name")
+	}
+	if p.ID <= 0 {
+		return fmt.Errorf("invalid id %d", p.ID)
+	}
+	if len(p.Types) == 0 || len(p.Types) > 2 {
+		return fmt.Errorf("expected 1 or 2 types, got %d", len(p.Types))
+	}
+	for _, t := range p.Types {
+		if t.Type.Name == "" {
+			return errors.New("type with empty name")
+		}
+		if !chart.IsValidType(t.Type.Name) {
+			return fmt.Errorf("type %s does not exist in generation %d", t.Type.Name, chart.Generation)
+		}
+	}
+	for _, name := range standardStats {
+		found := false
+		for _, s := range p.Stats {
+			if s.Stat.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("missing stat %s", name)
+		}
+	}
+	for _, entry := range p.Moves {
+		if entry.Move.Name == "" {
+			return errors.New("learnset entry with empty move name")
+		}
+	}
+	return nil
+}
+
+// Move is a move as stored in the data files.
+type Move struct {
+	ID            int             `json:"id"`
+	Name          string          `json:"name"`
+	Type          NamedResource   `json:"type"`
+	DamageClass   NamedResource   `json:"damage_class"`
+	Power         *int            `json:"power"`
+	Accuracy      *int            `json:"accuracy"`
+	PP            int             `json:"pp"`
+	Priority      int             `json:"priority"`
+	EffectChance  *int            `json:"effect_chance"`
+	Names         []LocalizedName `json:"names"`
+	EffectEntries []EffectEntry   `json:"effect_entries"`
+}
+
+// DisplayName returns the move name in the given language, falling back to
+// English and then to the API name.
+func (m *Move) DisplayName(lang string) string {
+	return translatedName(m.Names, lang, m.Name)
+}
+
+// Effect returns the effect text in the given language, falling back to English.
+func (m *Move) Effect(lang string) string {
+	return effectByLang(m.EffectEntries, lang)
+}
+
+// validate checks that the move has the fields every handler relies on.
+func (m *Move) validate() error {
+	if m.Name == "" {
+		return errors.New("missing name")
+	}
+	if m.Type.Name == "" {
+		return errors.New("missing type")
+	}
+	switch m.DamageClass.Name {
+	case "physical", "special", "status":
+	default:
+		return fmt.Errorf("invalid damage class %q", m.DamageClass.Name)
+	}
+	if m.PP < 0 {
+		return fmt.Errorf("invalid pp %d", m.PP)
+	}
+	return nil
+}
+
+// FlavorText is an in-game description in a given language and version group.
+type FlavorText struct {
+	FlavorText   string        `json:"flavor_text"`
+	Language     NamedResource `json:"language"`
+	VersionGroup NamedResource `json:"version_group"`
+}
+
+// Ability is an ability as stored in the data files.
+type Ability struct {
+	ID                int             `json:"id"`
+	Name              string          `json:"name"`
+	Generation        NamedResource   `json:"generation"`
+	Names             []LocalizedName `json:"names"`
+	EffectEntries     []EffectEntry   `json:"effect_entries"`
+	FlavorTextEntries []FlavorText    `json:"flavor_text_entries"`
+}
+
+// DisplayName returns the ability name in the given language, falling back to
+// English and then to the API name.
+func (a *Ability) DisplayName(lang string) string {
+	return translatedName(a.Names, lang, a.Name)
+}
+
+// Effect returns the effect text in the given language, falling back to English.
+func (a *Ability) Effect(lang string) string {
+	return effectByLang(a.EffectEntries, lang)
+}
+
+// ShortEffect returns the short effect text in the given language, falling
+// back to English.
+func (a *Ability) ShortEffect(lang string) string {
+	var english string
+	for _, entry := range a.EffectEntries {
+		if entry.Language.Name == lang && entry.ShortEffect != "" {
+			return entry.ShortEffect
+		}
+		if entry.Language.Name == "en" {
+			english = entry.ShortEffect
+		}
+	}
+	return english
+}
+
+// FlavorText returns the in-game description of a version group in the given
+// language, falling back to English.
+func (a *Ability) FlavorText(lang, versionGroup string) string {
+	var english string
+	for _, entry := range a.FlavorTextEntries {
+		if entry.VersionGroup.Name != versionGroup {
+			continue
+		}
+		if entry.Language.Name == lang {
+			return entry.FlavorText
+		}
+		if entry.Language.Name == "en" {
+			english = entry.FlavorText
+		}
+	}
+	return english
+}
+
+// Item is an item as stored in the data files.
+type Item struct {
+	ID            int             `json:"id"`
+	Name          string          `json:"name"`
+	Cost          int             `json:"cost"`
+	FlingPower    *int            `json:"fling_power"`
+	Category      NamedResource   `json:"category"`
+	Attributes    []NamedResource `json:"attributes"`
+	Names         []LocalizedName `json:"names"`
+	EffectEntries []EffectEntry   `json:"effect_entries"`
+	Sprites       struct {
+		Default string `json:"default"`
+	} `json:"sprites"`
+}
+
+// DisplayName returns the item name in the given language, falling back to
+// English and then to the API name.
+func (i *Item) DisplayName(lang string) string {
+	return translatedName(i.Names, lang, i.Name)
+}
+
+// Effect returns the effect text in the given language, falling back to English.
+func (i *Item) Effect(lang string) string {
+	return effectByLang(i.EffectEntries, lang)
+}
+
+// Machine maps a TM or HM item to the move it teaches in a version group.
+type Machine struct {
+	ID           int           `json:"id"`
+	Item         NamedResource `json:"item"`
+	Move         NamedResource `json:"move"`
+	VersionGroup NamedResource `json:"version_group"`
+}
+
+// Number returns the kind ("tm" or "hm") and number of the machine, parsed
+// from its item name. ok is false for items that do not follow the tmNN/hmNN
+// naming.
+func (m *Machine) Number() (kind string, number int, ok bool) {
+	name := m.Item.Name
+	if len(name) < 3 {
+		return "", 0, false
+	}
+	kind = name[:2]
+	if kind != "tm" && kind != "hm" {
+		return "", 0, false
+	}
+	number, err := strconv.Atoi(name[2:])
+	if err != nil {
+		return "", 0, false
+	}
+	return kind, number, true
+}
+
+// Encounter is where a Pokemon is found in one location area, per version,
+// as stored in the data files.
+type Encounter struct {
+	Pokemon        string                    `json:"pokemon"`
+	LocationArea   NamedResource             `json:"location_area"`
+	VersionDetails []EncounterVersionDetails `json:"version_details"`
+}
+
+// EncounterVersionDetails lists the encounter slots of an area in one version.
+type EncounterVersionDetails struct {
+	Version          NamedResource     `json:"version"`
+	MaxChance        int               `json:"max_chance"`
+	EncounterDetails []EncounterDetail `json:"encounter_details"`
+}
+
+// EncounterDetail is one encounter slot: how the Pokemon is met, at which
+// levels, under which conditions (time of day, swarm, radio...) and with
+// which chance.
+type EncounterDetail struct {
+	MinLevel        int             `json:"min_level"`
+	MaxLevel        int             `json:"max_level"`
+	Chance          int             `json:"chance"`
+	Method          NamedResource   `json:"method"`
+	ConditionValues []NamedResource `json:"condition_values"`
+}
+
+// Gender rates of species, in eighths female.
+const (
+	Genderless = -1
+	MaleOnly   = 0
+	FemaleOnly = 8
+)
+
+// Species is a Pokemon species as stored in the data files.
+type Species struct {
+	ID                 int             `json:"id"`
+	Name               string          `json:"name"`
+	GenderRate         int             `json:"gender_rate"`
+	IsBaby             bool            `json:"is_baby"`
+	IsLegendary        bool            `json:"is_legendary"`
+	IsMythical         bool            `json:"is_mythical"`
+	EggGroups          []NamedResource `json:"egg_groups"`
+	EvolvesFromSpecies *NamedResource  `json:"evolves_from_species"`
+	EvolutionChain     struct {
+		URL string `json:"url"`
+	} `json:"evolution_chain"`
+	Names []LocalizedName `json:"names"`
+}
+
+// EggGroupNames returns the names of the species' egg groups.
+func (s *Species) EggGroupNames() []string {
+	names := make([]string, 0, len(s.EggGroups))
+	for _, g := range s.EggGroups {
+		names = append(names, g.Name)
+	}
+	return names
+}
+
+// CanBreed reports whether the species can produce eggs with a partner other
+// than Ditto.
+func (s *Species) CanBreed() bool {
+	if s.GenderRate == Genderless {
+		return false
+	}
+	for _, g := range s.EggGroups {
+		if g.Name == "no-eggs" || g.Name == "ditto" {
+			return false
+		}
+	}
+	return len(s.EggGroups) > 0
+}
+
+// SharesEggGroup reports whether two species have an egg group in common.
+func (s *Species) SharesEggGroup(other *Species) bool {
+	for _, a := range s.EggGroups {
+		for _, b := range other.EggGroups {
+			if a.Name == b.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// EvolutionDetail is one way a species evolves from the previous stage of its
+// chain. Only the conditions that apply are set.
+type EvolutionDetail struct {
+	Trigger               NamedResource  `json:"trigger"`
+	MinLevel              *int           `json:"min_level"`
+	Item                  *NamedResource `json:"item"`
+	HeldItem              *NamedResource `json:"held_item"`
+	KnownMove             *NamedResource `json:"known_move"`
+	KnownMoveType         *NamedResource `json:"known_move_type"`
+	Location              *NamedResource `json:"location"`
+	PartySpecies          *NamedResource `json:"party_species"`
+	PartyType             *NamedResource `json:"party_type"`
+	TradeSpecies          *NamedResource `json:"trade_species"`
+	MinHappiness          *int           `json:"min_happiness"`
+	MinBeauty             *int           `json:"min_beauty"`
+	MinAffection          *int           `json:"min_affection"`
+	Gender                *int           `json:"gender"`
+	RelativePhysicalStats *int           `json:"relative_physical_stats"`
+	TimeOfDay             string         `json:"time_of_day"`
+	NeedsOverworldRain    bool           `json:"needs_overworld_rain"`
+	TurnUpsideDown        bool           `json:"turn_upside_down"`
+}
+
+// ChainLink is a species in an evolution chain and the species it evolves into.
+type ChainLink struct {
+	IsBaby           bool              `json:"is_baby"`
+	Species          NamedResource     `json:"species"`
+	EvolutionDetails []EvolutionDetail `json:"evolution_details"`
+	EvolvesTo        []ChainLink       `json:"evolves_to"`
+}
+
+// EvolutionChain is a family of species linked by evolution.
+type EvolutionChain struct {
+	ID    int       `json:"id"`
+	Chain ChainLink `json:"chain"`
+}
+
+// Species returns every species of the chain, parents before their evolutions.
+func (c *EvolutionChain) Species() []string {
+	var names []string
+	var walk func(link *ChainLink)
+	walk = func(link *ChainLink) {
+		names = append(names, link.Species.Name)
+		for i := range link.EvolvesTo {
+			walk(&link.EvolvesTo[i])
+		}
+	}
+	walk(&c.Chain)
+	return names
+}
+
+// decodeRecord converts a normalized raw document into a typed value.
+func decodeRecord(raw map[string]interface{}, v interface{}) error {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
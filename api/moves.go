@@ -5,25 +5,32 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+
+	"pokeproject/games"
 )
 
 // PokemonMoveEntry represents a move entry in the PokemonMovesResponse
 type PokemonMoveEntry struct {
-	Name           string `json:"name"`
-	DisplayName    string `json:"display_name"`
-	Type           string `json:"type"`
-	Power          *int   `json:"power"`
-	Accuracy       *int   `json:"accuracy"`
-	PP             int    `json:"pp"`
-	DamageClass    string `json:"damage_class"`
-	LearnMethod    string `json:"learn_method"`
-	LevelLearnedAt int    `json:"level_learned_at"`
+	Name           string        `json:"name"`
+	DisplayName    string        `json:"display_name"`
+	Type           string        `json:"type"`
+	Power          *int          `json:"power"`
+	Accuracy       *int          `json:"accuracy"`
+	PP             int           `json:"pp"`
+	DamageClass    string        `json:"damage_class"`
+	LearnMethod    string        `json:"learn_method"`
+	LevelLearnedAt int           `json:"level_learned_at"`
+	TMNumber       int           `json:"tm_number,omitempty"`
+	HMNumber       int           `json:"hm_number,omitempty"`
+	Tutors         []games.Tutor `json:"tutors,omitempty"`
+	Source         string        `json:"source,omitempty"`
 }
 
 // PokemonMovesResponse represents the API response for a Pokemon's moves
 type PokemonMovesResponse struct {
-	Pokemon string             `json:"pokemon"`
-	Moves   []PokemonMoveEntry `json:"moves"`
+	Pokemon       string             `json:"pokemon"`
+	PreEvolutions []string           `json:"pre_evolutions,omitempty"`
+	Moves         []PokemonMoveEntry `json:"moves"`
 }
 
 // MoveResponse represents the API response for a single move
@@ -49,7 +56,7 @@ func GetMoveByNameCached(w http.ResponseWriter, r *http.Request, cache *Cache) {
 		return
 	}
 
-	data, ok := cache.MovesRaw[name]
+	data, ok := cache.Moves[name]
 	if !ok {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusNotFound)
@@ -58,138 +65,173 @@ func GetMoveByNameCached(w http.ResponseWriter, r *http.Request, cache *Cache) {
 	}
 
 	move := MoveResponse{
-		Name:        getStringField(data, "name"),
-		DisplayName: getTranslatedName(data, lang),
-	}
-	if typeObj, ok := data["type"].(map[string]interface{}); ok {
-		move.Type = getStringField(typeObj, "name")
-	}
-	move.Power = getIntPtrField(data, "power")
-	move.Accuracy = getIntPtrField(data, "accuracy")
-	if pp, ok := toInt(data["pp"]); ok {
-		move.PP = pp
-	}
-	if dcObj, ok := data["damage_class"].(map[string]interface{}); ok {
-		move.DamageClass = getStringField(dcObj, "name")
+		Name:        data.Name,
+		DisplayName: data.DisplayName(lang),
+		Type:        data.Type.Name,
+		Power:       data.Power,
+		Accuracy:    data.Accuracy,
+		PP:          data.PP,
+		DamageClass: data.DamageClass.Name,
+		Effect:      data.Effect(lang),
 	}
-	move.Effect = extractEffectByLang(data, lang)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(move)
 }
 
-// GetPokemonMovesCached returns the moves a Pokemon can learn in HG/SS from cache
+// GetPokemonMovesCached returns the moves a Pokemon can learn in the cache's game,
+// one entry per way to learn each move. ?method= keeps only one learn method
+// (e.g. tutor) and ?location= only the tutor moves taught at a location (e.g.
+// battle-frontier). With ?include_prevo=true the moves of its pre-evolutions
+// are added too, and every entry names the Pokemon it is learned by in source.
 func GetPokemonMovesCached(w http.ResponseWriter, r *http.Request, cache *Cache) {
 	path := r.URL.Path
 	path = strings.TrimPrefix(path, "/api/pokemon/")
 	name := strings.TrimSuffix(path, "/moves")
 	name = strings.ToLower(name)
 	lang := getLang(r)
+	method := strings.ToLower(r.URL.Query().Get("method"))
+	location := strings.ToLower(r.URL.Query().Get("location"))
 
 	if name == "" {
 		http.Error(w, `{"error": "Pokemon name is required"}`, http.StatusBadRequest)
 		return
 	}
 
-	var pokemonData map[string]interface{}
-	for _, data := range cache.PokemonRaw {
-		if strings.ToLower(getStringField(data, "name")) == name {
-			pokemonData = data
-			break
+	if location != "" {
+		if method != "" && method != "tutor" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "location can only be used with method=tutor"})
+			return
 		}
+		if !isTutorLocation(cache.Game, location) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("unknown tutor location: %s", location)})
+			return
+		}
+		method = "tutor"
 	}
 
-	if pokemonData == nil {
+	filter, err := parseBadgeFilter(r, cache)
+	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Pokemon not found: %s", name)})
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
 		return
 	}
 
-	movesRaw, ok := pokemonData["moves"].([]interface{})
-	if !ok {
-		resp := PokemonMovesResponse{Pokemon: name, Moves: []PokemonMoveEntry{}}
+	pokemon := cache.lookupPokemon(name)
+	if pokemon == nil {
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(resp)
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Pokemon not found: %s", name)})
 		return
 	}
 
-	var entries []PokemonMoveEntry
-	for _, moveRaw := range movesRaw {
-		moveMap, ok := moveRaw.(map[string]interface{})
-		if !ok {
-			continue
-		}
-		moveObj, ok := moveMap["move"].(map[string]interface{})
-		if !ok {
-			continue
+	entries := pokemonMoveEntries(pokemon, cache, lang, method, location, filter)
+	var prevos []string
+	if r.URL.Query().Get("include_prevo") == "true" {
+		// Moves of the Pokemon itself win over the same move from a
+		// pre-evolution; seen maps each move to the Pokemon it is listed for
+		seen := make(map[string]string, len(entries))
+		for i := range entries {
+			entries[i].Source = pokemon.Name
+			seen[entries[i].Name] = pokemon.Name
 		}
-		moveName := getStringField(moveObj, "name")
-		if moveName == "" {
-			continue
+		for _, prevo := range cache.PreEvolutions(pokemon) {
+			prevos = append(prevos, prevo.Name)
+			for _, entry := range pokemonMoveEntries(prevo, cache, lang, method, location, filter) {
+				if source, ok := seen[entry.Name]; ok && source != prevo.Name {
+					continue
+				}
+				seen[entry.Name] = prevo.Name
+				entry.Source = prevo.Name
+				entries = append(entries, entry)
+			}
 		}
+	}
+
+	if entries == nil {
+		entries = []PokemonMoveEntry{}
+	}
 
-		vgDetails, ok := moveMap["version_group_details"].([]interface{})
-		if !ok {
+	resp := PokemonMovesResponse{Pokemon: pokemon.Name, PreEvolutions: prevos, Moves: entries}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// pokemonMoveEntries lists the moves a Pokemon learns in the cache's game,
+// one entry per learn method, filtered by learn method, tutor location and
+// badge progression when given.
+func pokemonMoveEntries(p *Pokemon, cache *Cache, lang, method, location string, filter *badgeFilter) []PokemonMoveEntry {
+	var entries []PokemonMoveEntry
+	for _, learned := range p.Moves.AllForVersionGroup(cache.Game.VersionGroup) {
+		if method != "" && learned.Method != method {
 			continue
 		}
+		entry := PokemonMoveEntry{
+			Name:           learned.Name,
+			LearnMethod:    learned.Method,
+			LevelLearnedAt: learned.Level,
+		}
 
-		for _, vgd := range vgDetails {
-			detail, ok := vgd.(map[string]interface{})
-			if !ok {
-				continue
-			}
-			vgObj, ok := detail["version_group"].(map[string]interface{})
-			if !ok {
-				continue
+		if move, ok := cache.Moves[strings.ToLower(learned.Name)]; ok {
+			entry.DisplayName = move.DisplayName(lang)
+			entry.Type = move.Type.Name
+			entry.Power = move.Power
+			entry.Accuracy = move.Accuracy
+			entry.PP = move.PP
+			entry.DamageClass = move.DamageClass.Name
+		}
+		if entry.DisplayName == "" {
+			entry.DisplayName = learned.Name
+		}
+		if learned.Method == "machine" {
+			if m := cache.MachineForMove(learned.Name); m != nil {
+				switch kind, number, _ := m.Number(); kind {
+				case "tm":
+					entry.TMNumber = number
+				case "hm":
+					entry.HMNumber = number
+				}
 			}
-			if getStringField(vgObj, "name") != "heartgold-soulsilver" {
+		}
+		if learned.Method == "tutor" {
+			entry.Tutors = tutorsAt(cache.Game, learned.Name, location)
+			if location != "" && len(entry.Tutors) == 0 {
 				continue
 			}
+		}
+		if !filter.moveAvailable(entry) {
+			continue
+		}
 
-			learnMethod := ""
-			if mlm, ok := detail["move_learn_method"].(map[string]interface{}); ok {
-				learnMethod = getStringField(mlm, "name")
-			}
-			levelLearnedAt := 0
-			if lvl, ok := toInt(detail["level_learned_at"]); ok {
-				levelLearnedAt = lvl
-			}
-
-			entry := PokemonMoveEntry{
-				Name:           moveName,
-				LearnMethod:    learnMethod,
-				LevelLearnedAt: levelLearnedAt,
-			}
+		entries = append(entries, entry)
+	}
 
-			if moveData, ok := cache.MovesRaw[strings.ToLower(moveName)]; ok {
-				entry.DisplayName = getTranslatedName(moveData, lang)
-				if typeObj, ok := moveData["type"].(map[string]interface{}); ok {
-					entry.Type = getStringField(typeObj, "name")
-				}
-				entry.Power = getIntPtrField(moveData, "power")
-				entry.Accuracy = getIntPtrField(moveData, "accuracy")
-				if pp, ok := toInt(moveData["pp"]); ok {
-					entry.PP = pp
-				}
-				if dcObj, ok := moveData["damage_class"].(map[string]interface{}); ok {
-					entry.DamageClass = getStringField(dcObj, "name")
-				}
-			}
-			if entry.DisplayName == "" {
-				entry.DisplayName = moveName
-			}
+	return entries
+}
 
-			entries = append(entries, entry)
-			break
+// tutorsAt returns the tutors teaching a move in a game, limited to a location
+// if one is given.
+func tutorsAt(game games.Game, move, location string) []games.Tutor {
+	var found []games.Tutor
+	for _, t := range game.TutorsForMove(move) {
+		if location == "" || t.Location == location {
+			found = append(found, t)
 		}
 	}
+	return found
+}
 
-	if entries == nil {
-		entries = []PokemonMoveEntry{}
+// isTutorLocation reports whether any tutor of the game is at a location.
+func isTutorLocation(game games.Game, location string) bool {
+	for _, t := range game.Tutors() {
+		if t.Location == location {
+			return true
+		}
 	}
-
-	resp := PokemonMovesResponse{Pokemon: name, Moves: entries}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(resp)
+	return false
 }
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// movesTestCache has a Charmander that learns Flash and Ember both by
+// level-up and by TM. TM70 (Flash) is available from the start; Ember has no
+// TM in the data, so it needs the default TM badges.
+func movesTestCache(t *testing.T) *Cache {
+	game := gameOf(t)
+	return testCache(t, rawData{
+		Pokemon: []map[string]interface{}{
+			testPokemon(game, 4, "charmander", []string{"fire"},
+				LearnedMove{Name: "flash", Method: "level-up", Level: 40},
+				LearnedMove{Name: "flash", Method: "machine"},
+				LearnedMove{Name: "ember", Method: "level-up", Level: 7},
+				LearnedMove{Name: "ember", Method: "machine"},
+			),
+		},
+		Moves: []map[string]interface{}{
+			testMove(148, "flash", "normal", "status", 0),
+			testMove(52, "ember", "fire", "special", 40),
+		},
+		Machines: []map[string]interface{}{{
+			"item":          map[string]interface{}{"name": "tm70"},
+			"move":          map[string]interface{}{"name": "flash"},
+			"version_group": map[string]interface{}{"name": game.VersionGroup},
+		}},
+	})
+}
+
+// getMoves calls GET /api/pokemon/{name}/moves and returns the entries.
+func getMoves(t *testing.T, cache *Cache, url string) []PokemonMoveEntry {
+	t.Helper()
+	w := httptest.NewRecorder()
+	GetPokemonMovesCached(w, httptest.NewRequest(http.MethodGet, url, nil), cache)
+	if w.Code != http.StatusOK {
+		t.Fatalf("%s: got status %d: %s", url, w.Code, w.Body)
+	}
+	var resp PokemonMovesResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp.Moves
+}
+
+// moveMethods returns the entries as sorted "move/method" strings.
+func moveMethods(entries []PokemonMoveEntry) []string {
+	list := []string{}
+	for _, e := range entries {
+		list = append(list, e.Name+"/"+e.LearnMethod)
+	}
+	sort.Strings(list)
+	return list
+}
+
+func TestPokemonMovesBadges(t *testing.T) {
+	cache := movesTestCache(t)
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"ember/level-up", "ember/machine", "flash/level-up", "flash/machine"}},
+		// Level cap 10: Flash only by TM70, Ember only by level-up
+		{"?badges=0", []string{"ember/level-up", "flash/machine"}},
+		{"?badges=8", []string{"ember/level-up", "ember/machine", "flash/level-up", "flash/machine"}},
+	}
+	for _, tt := range tests {
+		got := moveMethods(getMoves(t, cache, "/api/pokemon/charmander/moves"+tt.query))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: got %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestPokemonMovesMachineNumbers(t *testing.T) {
+	game := gameOf(t)
+	cache := testCache(t, rawData{
+		Pokemon: []map[string]interface{}{
+			testPokemon(game, 4, "charmander", []string{"fire"},
+				LearnedMove{Name: "flamethrower", Method: "level-up", Level: 31},
+				LearnedMove{Name: "flamethrower", Method: "machine"},
+			),
+		},
+		Moves: []map[string]interface{}{testMove(53, "flamethrower", "fire", "special", 95)},
+		Machines: []map[string]interface{}{{
+			"item":          map[string]interface{}{"name": "tm35"},
+			"move":          map[string]interface{}{"name": "flamethrower"},
+			"version_group": map[string]interface{}{"name": game.VersionGroup},
+		}},
+	})
+
+	var tmNumber int
+	for _, e := range getMoves(t, cache, "/api/pokemon/charmander/moves?method=machine") {
+		if e.Name == "flamethrower" {
+			tmNumber = e.TMNumber
+		}
+	}
+	if tmNumber != 35 {
+		t.Errorf("flamethrower: got tm_number %d, want 35", tmNumber)
+	}
+}
+
+func TestPokemonMovesTutors(t *testing.T) {
+	game := gameOf(t)
+	cache := testCache(t, rawData{
+		Pokemon: []map[string]interface{}{
+			testPokemon(game, 4, "charmander", []string{"fire"},
+				LearnedMove{Name: "fire-punch", Method: "egg"},
+				LearnedMove{Name: "fire-punch", Method: "tutor"},
+				LearnedMove{Name: "headbutt", Method: "level-up", Level: 1},
+				LearnedMove{Name: "headbutt", Method: "tutor"},
+			),
+		},
+		Moves: []map[string]interface{}{
+			testMove(7, "fire-punch", "fire", "physical", 75),
+			testMove(29, "headbutt", "normal", "physical", 70),
+		},
+	})
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"?method=tutor", []string{"fire-punch/tutor", "headbutt/tutor"}},
+		{"?location=battle-frontier", []string{"fire-punch/tutor"}},
+		{"?method=tutor&location=ilex-forest", []string{"headbutt/tutor"}},
+	}
+	for _, tt := range tests {
+		got := moveMethods(getMoves(t, cache, "/api/pokemon/charmander/moves"+tt.query))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: got %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
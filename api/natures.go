@@ -0,0 +1,43 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"pokeproject/stats"
+)
+
+// NatureResponse is the API representation of a nature.
+type NatureResponse struct {
+	Name        string             `json:"name"`
+	DisplayName string             `json:"display_name"`
+	Increased   string             `json:"increased,omitempty"`
+	Decreased   string             `json:"decreased,omitempty"`
+	Multipliers map[string]float64 `json:"multipliers"`
+}
+
+// GetNatures handles GET /api/natures. Names are localized with ?lang=.
+func GetNatures(w http.ResponseWriter, r *http.Request) {
+	lang := getLang(r)
+	natures := make([]NatureResponse, 0, len(stats.Natures))
+	for _, n := range stats.Natures {
+		natures = append(natures, buildNatureResponse(n, lang))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(natures)
+}
+
+// buildNatureResponse builds the API representation of a nature. Neutral
+// natures have no increased or decreased stat.
+func buildNatureResponse(n stats.Nature, lang string) NatureResponse {
+	resp := NatureResponse{
+		Name:        n.Name,
+		DisplayName: n.DisplayName(lang),
+		Multipliers: n.Modifiers(),
+	}
+	if !n.Neutral() {
+		resp.Increased = n.Increased
+		resp.Decreased = n.Decreased
+	}
+	return resp
+}
@@ -4,30 +4,48 @@ import (
 	"encoding/json"
 	"net/http"
 	"strings"
+
+	"pokeproject/stats"
 )
 
 // PokemonListItem represents a Pokemon in the list view
 type PokemonListItem struct {
-	ID         int    `json:"id"`
-	Name       string `json:"name"`
-	RegionalID int    `json:"regional_id"`
-	Types      []struct {
-		Slot int `json:"slot"`
-		Type struct {
-			Name string `json:"name"`
-		} `json:"type"`
-	} `json:"types"`
-	Sprites struct {
+	ID         int           `json:"id"`
+	Name       string        `json:"name"`
+	RegionalID int           `json:"regional_id"`
+	Types      []PokemonType `json:"types"`
+	Sprites    struct {
 		FrontDefault string `json:"front_default"`
 	} `json:"sprites"`
 }
 
-// GetPokemonListCached returns all Pokemon from the in-memory cache
+// PokemonDetail represents the API response for a single Pokemon
+type PokemonDetail struct {
+	ID             int              `json:"id"`
+	Name           string           `json:"name"`
+	RegionalID     int              `json:"regional_id"`
+	BaseExperience int              `json:"base_experience"`
+	Height         int              `json:"height"`
+	Weight         int              `json:"weight"`
+	Types          []PokemonType    `json:"types"`
+	Abilities      []PokemonAbility `json:"abilities"`
+	Stats          []Stat           `json:"stats"`
+	Sprites        Sprites          `json:"sprites"`
+	Nature         *NatureResponse  `json:"nature,omitempty"`
+}
+
+// GetPokemonListCached returns all Pokemon from the in-memory cache. With
+// ?badges= only the Pokemon obtainable with that many badges are listed.
 func GetPokemonListCached(w http.ResponseWriter, r *http.Request, cache *Cache) {
-	var list []SearchMatchItem
-	for _, data := range cache.PokemonRaw {
-		list = append(list, buildSearchMatchItem(data))
+	filter, err := parseBadgeFilter(r, cache)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
 	}
+
+	list := filter.filterSearchItems(cache.searchItems)
 	if list == nil {
 		list = []SearchMatchItem{}
 	}
@@ -43,7 +61,8 @@ func ExtractPokemonName(path string) string {
 	return strings.TrimPrefix(path, "/pokemon/")
 }
 
-// GetPokemonByNameCached returns a specific Pokemon by name from cache
+// GetPokemonByNameCached returns a specific Pokemon by name or National Pokédex number from cache.
+// With ?nature= the response includes the multiplier the nature applies to each stat.
 func GetPokemonByNameCached(w http.ResponseWriter, r *http.Request, cache *Cache) {
 	name := strings.ToLower(ExtractPokemonName(r.URL.Path))
 
@@ -52,14 +71,25 @@ func GetPokemonByNameCached(w http.ResponseWriter, r *http.Request, cache *Cache
 		return
 	}
 
-	for _, data := range cache.PokemonRaw {
-		if strings.ToLower(getStringField(data, "name")) == name {
-			// Build a detailed response from raw data
-			detail := buildPokemonDetail(data)
+	var nature *NatureResponse
+	if param := r.URL.Query().Get("nature"); param != "" {
+		n, err := stats.LookupNature(param)
+		if err != nil {
 			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(detail)
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
 			return
 		}
+		resp := buildNatureResponse(n, getLang(r))
+		nature = &resp
+	}
+
+	if p := cache.lookupPokemon(name); p != nil {
+		detail := buildPokemonDetail(p)
+		detail.Nature = nature
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(detail)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -67,21 +97,18 @@ func GetPokemonByNameCached(w http.ResponseWriter, r *http.Request, cache *Cache
 	json.NewEncoder(w).Encode(map[string]string{"error": "Pokemon not found: " + name})
 }
 
-// buildPokemonDetail builds a detailed Pokemon response from raw Firestore data
-func buildPokemonDetail(data map[string]interface{}) map[string]interface{} {
-	result := make(map[string]interface{})
-
-	// Copy basic fields
-	result["id"] = data["id"]
-	result["name"] = data["name"]
-	result["regional_id"] = data["regional_id"]
-	result["base_experience"] = data["base_experience"]
-	result["height"] = data["height"]
-	result["weight"] = data["weight"]
-	result["types"] = data["types"]
-	result["abilities"] = data["abilities"]
-	result["stats"] = data["stats"]
-	result["sprites"] = data["sprites"]
-
-	return result
+// buildPokemonDetail builds a detailed Pokemon response
+func buildPokemonDetail(p *Pokemon) PokemonDetail {
+	return PokemonDetail{
+		ID:             p.ID,
+		Name:           p.Name,
+		RegionalID:     p.RegionalID,
+		BaseExperience: p.BaseExperience,
+		Height:         p.Height,
+		Weight:         p.Weight,
+		Types:          p.Types,
+		Abilities:      p.Abilities,
+		Stats:          p.Stats,
+		Sprites:        p.Sprites,
+	}
 }
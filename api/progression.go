@@ -0,0 +1,308 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"pokeproject/games"
+)
+
+// ProgressionMachine is a TM or HM available at a checkpoint.
+type ProgressionMachine struct {
+	Kind   string `json:"kind"`
+	Number int    `json:"number"`
+	Move   string `json:"move"`
+}
+
+// ProgressionCheckpoint is what a playthrough has access to with a number of
+// badges.
+type ProgressionCheckpoint struct {
+	Badges   int                  `json:"badges"`
+	LevelCap int                  `json:"level_cap"`
+	Flags    []games.StoryFlag    `json:"flags"`
+	HMs      []ProgressionMachine `json:"hms"`
+	TMs      []ProgressionMachine `json:"tms"`
+	Tutors   []string             `json:"tutors"`
+	Gifts    []games.Gift         `json:"gifts"`
+	EggMoves bool                 `json:"egg_moves"`
+}
+
+// ProgressionResponse is the API response for a game's badge progression.
+type ProgressionResponse struct {
+	Game        string                  `json:"game"`
+	MaxBadges   int                     `json:"max_badges"`
+	Checkpoints []ProgressionCheckpoint `json:"checkpoints"`
+}
+
+// badgeFilter hides what a playthrough cannot have yet. obtainable is nil
+// when no encounter data is loaded, in which case every Pokemon counts as
+// obtainable.
+type badgeFilter struct {
+	progression *games.Progression
+	badges      int
+	levelCap    int
+	obtainable  map[string]bool
+}
+
+// GetProgressionCached handles GET /api/progression: the level cap, story
+// flags, TMs, HMs, tutors and gifts available at each badge count, or only at
+// the one given with ?badges=.
+func GetProgressionCached(w http.ResponseWriter, r *http.Request, cache *Cache) {
+	progression := cache.Game.Progression()
+	if progression == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("No badge progression for %s", cache.Game.Name)})
+		return
+	}
+
+	first, last := 0, progression.MaxBadges
+	if filter, err := parseBadgeFilter(r, cache); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	} else if filter != nil {
+		first, last = filter.badges, filter.badges
+	}
+
+	resp := ProgressionResponse{
+		Game:        cache.Game.Key,
+		MaxBadges:   progression.MaxBadges,
+		Checkpoints: []ProgressionCheckpoint{},
+	}
+	for badges := first; badges <= last; badges++ {
+		resp.Checkpoints = append(resp.Checkpoints, buildCheckpoint(progression, badges, cache))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// buildCheckpoint lists what is available with a number of badges.
+func buildCheckpoint(p *games.Progression, badges int, cache *Cache) ProgressionCheckpoint {
+	cp := ProgressionCheckpoint{
+		Badges:   badges,
+		LevelCap: p.LevelCap(badges),
+		Flags:    p.FlagsReached(badges),
+		HMs:      []ProgressionMachine{},
+		TMs:      []ProgressionMachine{},
+		Tutors:   []string{},
+		Gifts:    p.GiftsReceived(badges),
+		EggMoves: badges >= p.EggBadges,
+	}
+	if cp.Flags == nil {
+		cp.Flags = []games.StoryFlag{}
+	}
+	if cp.Gifts == nil {
+		cp.Gifts = []games.Gift{}
+	}
+
+	for _, m := range cache.Machines {
+		kind, number, ok := m.Number()
+		if !ok || !p.MachineUnlocked(kind, number, badges) {
+			continue
+		}
+		entry := ProgressionMachine{Kind: kind, Number: number, Move: m.Move.Name}
+		if kind == "hm" {
+			cp.HMs = append(cp.HMs, entry)
+		} else {
+			cp.TMs = append(cp.TMs, entry)
+		}
+	}
+	sort.Slice(cp.TMs, func(i, j int) bool { return cp.TMs[i].Number < cp.TMs[j].Number })
+	sort.Slice(cp.HMs, func(i, j int) bool { return cp.HMs[i].Number < cp.HMs[j].Number })
+
+	seen := make(map[string]bool)
+	for _, t := range cache.Game.Tutors() {
+		if !seen[t.Location] && p.TutorUnlocked(t.Location, badges) {
+			seen[t.Location] = true
+			cp.Tutors = append(cp.Tutors, t.Location)
+		}
+	}
+	return cp
+}
+
+// parseBadgeFilter reads ?badges=. It returns nil if the parameter is not
+// given, and an error if it is invalid or the game has no progression model.
+func parseBadgeFilter(r *http.Request, cache *Cache) (*badgeFilter, error) {
+	param := strings.TrimSpace(r.URL.Query().Get("badges"))
+	if param == "" {
+		return nil, nil
+	}
+	progression := cache.Game.Progression()
+	if progression == nil {
+		return nil, fmt.Errorf("badges filter is not available for %s", cache.Game.Name)
+	}
+	badges, err := strconv.Atoi(param)
+	if err != nil {
+		return nil, fmt.Errorf("invalid badges: %s", param)
+	}
+	if err := progression.ValidateBadges(badges); err != nil {
+		return nil, err
+	}
+	return &badgeFilter{
+		progression: progression,
+		badges:      badges,
+		levelCap:    progression.LevelCap(badges),
+		obtainable:  obtainablePokemon(cache, progression, badges),
+	}, nil
+}
+
+// pokemonAvailable reports whether a Pokemon can be obtained. A nil filter
+// allows everything.
+func (f *badgeFilter) pokemonAvailable(name string) bool {
+	if f == nil || f.obtainable == nil {
+		return true
+	}
+	return f.obtainable[name]
+}
+
+// moveAvailable reports whether a move entry can be learned: level-up moves up
+// to the obedience level cap, TMs and HMs already obtainable, tutors already
+// reachable and egg moves once the Day-Care is open. Entries are per learn
+// method, so a move stays available while any of its methods is. A nil filter
+// allows everything.
+func (f *badgeFilter) moveAvailable(entry PokemonMoveEntry) bool {
+	if f == nil {
+		return true
+	}
+	p := f.progression
+	switch entry.LearnMethod {
+	case "level-up":
+		return entry.LevelLearnedAt <= f.levelCap
+	case "machine":
+		if entry.HMNumber > 0 {
+			return p.MachineUnlocked("hm", entry.HMNumber, f.badges)
+		}
+		if entry.TMNumber > 0 {
+			return p.MachineUnlocked("tm", entry.TMNumber, f.badges)
+		}
+		return f.badges >= p.DefaultTMBadges
+	case "tutor":
+		for _, t := range entry.Tutors {
+			if p.TutorUnlocked(t.Location, f.badges) {
+				return true
+			}
+		}
+		return false
+	case "egg":
+		return f.badges >= p.EggBadges
+	}
+	return true
+}
+
+// availableMove returns one of the given moves that a Pokemon can learn by a
+// method already available, or "".
+func (f *badgeFilter) availableMove(p *Pokemon, cache *Cache, moves map[string]bool) string {
+	for _, entry := range pokemonMoveEntries(p, cache, "en", "", "", f) {
+		if moves[entry.Name] {
+			return entry.Name
+		}
+	}
+	return ""
+}
+
+// filterSearchItems keeps the items of obtainable Pokemon.
+func (f *badgeFilter) filterSearchItems(items []SearchMatchItem) []SearchMatchItem {
+	if f == nil || f.obtainable == nil {
+		return items
+	}
+	filtered := []SearchMatchItem{}
+	for _, item := range items {
+		if f.obtainable[item.Name] {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// obtainablePokemon returns the names of the Pokemon that can be obtained
+// with the given badges: caught in a reachable area with a usable method at a
+// level that obeys, received as a gift, evolved from one of those without
+// going over the level cap, or bred from one once the Day-Care is open.
+// It returns nil if no encounter data is loaded.
+func obtainablePokemon(cache *Cache, p *games.Progression, badges int) map[string]bool {
+	if len(cache.Encounters) == 0 {
+		return nil
+	}
+	levelCap := p.LevelCap(badges)
+
+	species := make(map[string]bool)
+	add := func(name string) {
+		if pokemon := cache.PokemonByName(name); pokemon != nil && pokemon.Species.Name != "" {
+			species[pokemon.Species.Name] = true
+		} else {
+			species[name] = true
+		}
+	}
+	for _, enc := range cache.Encounters {
+		if !p.AreaUnlocked(enc.LocationArea.Name, badges) {
+			continue
+		}
+		for _, vd := range enc.VersionDetails {
+			for _, d := range vd.EncounterDetails {
+				if p.MethodUnlocked(d.Method.Name, badges) && d.MinLevel <= levelCap {
+					add(enc.Pokemon)
+				}
+			}
+		}
+	}
+	for _, g := range p.GiftsReceived(badges) {
+		add(g.Pokemon)
+	}
+
+	for _, chain := range cache.Evolutions {
+		if badges >= p.EggBadges {
+			for _, s := range chain.Species() {
+				if species[s] {
+					species[chain.Chain.Species.Name] = true
+					break
+				}
+			}
+		}
+		markEvolutions(chain.Chain, false, levelCap, species)
+	}
+
+	obtainable := make(map[string]bool)
+	for _, pokemon := range cache.Pokemon {
+		name := pokemon.Species.Name
+		if name == "" {
+			name = pokemon.Name
+		}
+		if species[name] {
+			obtainable[pokemon.Name] = true
+		}
+	}
+	return obtainable
+}
+
+// markEvolutions marks the species a link evolves into as obtainable when
+// the link's species is, unless every way to evolve needs a level above the
+// cap.
+func markEvolutions(link ChainLink, parentObtainable bool, levelCap int, species map[string]bool) {
+	if parentObtainable && evolvesUnderCap(link.EvolutionDetails, levelCap) {
+		species[link.Species.Name] = true
+	}
+	for _, child := range link.EvolvesTo {
+		markEvolutions(child, species[link.Species.Name], levelCap, species)
+	}
+}
+
+// evolvesUnderCap reports whether one of the ways to evolve needs no level
+// above the cap.
+func evolvesUnderCap(details []EvolutionDetail, levelCap int) bool {
+	if len(details) == 0 {
+		return true
+	}
+	for _, d := range details {
+		if d.MinLevel == nil || *d.MinLevel <= levelCap {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,610 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// RunRequest is the body of POST /api/runs. Both clauses default to on.
+type RunRequest struct {
+	Name          string `json:"name"`
+	DupesClause   *bool  `json:"dupes_clause"`
+	SpeciesClause *bool  `json:"species_clause"`
+}
+
+// RunEncounterRequest is the body of POST /api/runs/{id}/encounters. Status
+// defaults to caught; Ability and Moves are only kept for living Pokemon.
+type RunEncounterRequest struct {
+	Area     string   `json:"area"`
+	Pokemon  string   `json:"pokemon"`
+	Nickname string   `json:"nickname"`
+	Status   string   `json:"status"`
+	Ability  string   `json:"ability"`
+	Moves    []string `json:"moves"`
+}
+
+// RunEncounterUpdate is the body of PATCH /api/runs/{id}/encounters/{area}.
+// Fields left out are not changed; Pokemon may only name a member of the
+// same evolution family, for when it evolves.
+type RunEncounterUpdate struct {
+	Pokemon  *string   `json:"pokemon"`
+	Nickname *string   `json:"nickname"`
+	Status   *string   `json:"status"`
+	Ability  *string   `json:"ability"`
+	Moves    *[]string `json:"moves"`
+}
+
+// RunPartyRequest is the body of PUT /api/runs/{id}/party: the areas of the
+// living Pokemon to carry, in order.
+type RunPartyRequest struct {
+	Party []string `json:"party"`
+}
+
+// runError is a request a run cannot accept, answered with status.
+type runError struct {
+	status int
+	msg    string
+}
+
+func (e *runError) Error() string {
+	return e.msg
+}
+
+// conflictf returns a runError for a change the run's rules forbid.
+func conflictf(format string, args ...interface{}) error {
+	return &runError{status: http.StatusConflict, msg: fmt.Sprintf(format, args...)}
+}
+
+// invalidf returns a runError for an invalid request.
+func invalidf(format string, args ...interface{}) error {
+	return &runError{status: http.StatusBadRequest, msg: fmt.Sprintf(format, args...)}
+}
+
+// GetRuns handles GET /api/runs: every Nuzlocke run, oldest first.
+func GetRuns(w http.ResponseWriter, r *http.Request, runs *RunStore) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(runs.List())
+}
+
+// CreateRunCached handles POST /api/runs: starts a Nuzlocke run of the game
+// selected with ?game=.
+func CreateRunCached(w http.ResponseWriter, r *http.Request, runs *RunStore, cache *Cache) {
+	var req RunRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	run := &Run{
+		Name:          strings.TrimSpace(req.Name),
+		Game:          cache.Game.Key,
+		DupesClause:   req.DupesClause == nil || *req.DupesClause,
+		SpeciesClause: req.SpeciesClause == nil || *req.SpeciesClause,
+		Encounters:    []RunEncounter{},
+		Party:         []string{},
+	}
+	if run.Name == "" {
+		run.Name = cache.Game.Name + " Nuzlocke"
+	}
+	run, err := runs.Create(run)
+	if err != nil {
+		writeRunError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(run)
+}
+
+// GetRun handles GET /api/runs/{id}.
+func GetRun(w http.ResponseWriter, r *http.Request, runs *RunStore) {
+	id, _ := runPath(r)
+	if id == "" {
+		http.Error(w, `{"error": "Run ID is required"}`, http.StatusBadRequest)
+		return
+	}
+	run := runs.Get(id)
+	if run == nil {
+		writeRunError(w, errRunNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(run)
+}
+
+// DeleteRun handles DELETE /api/runs/{id}.
+func DeleteRun(w http.ResponseWriter, r *http.Request, runs *RunStore) {
+	id, _ := runPath(r)
+	if err := runs.Delete(id); err != nil {
+		writeRunError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddRunEncounter handles POST /api/runs/{id}/encounters: records the first
+// encounter on a location area. The Pokemon must be found in the area in the
+// run's game, or be the gift received there. Later encounters on the same
+// area, encounters of an evolution family already caught under the dupes
+// clause and catches of a species already alive under the species clause
+// are refused with 409; a refused dupe leaves the area open.
+func AddRunEncounter(w http.ResponseWriter, r *http.Request, runs *RunStore, store *Store) {
+	run, cache, ok := runForRequest(w, r, runs, store)
+	if !ok {
+		return
+	}
+
+	var req RunEncounterRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	encounter, pokemon, err := resolveRunEncounter(req, cache)
+	if err != nil {
+		writeRunError(w, err)
+		return
+	}
+
+	run, err = runs.Update(run.ID, func(run *Run) error {
+		if prev := run.Encounter(encounter.Area); prev != nil {
+			return conflictf("first encounter on %s was already recorded: %s", prev.Area, prev.Pokemon)
+		}
+		if run.DupesClause {
+			if err := checkDupesClause(run, cache, pokemon); err != nil {
+				return err
+			}
+		}
+		if encounter.Alive() {
+			if run.SpeciesClause {
+				if err := checkSpeciesClause(run, cache, pokemon, ""); err != nil {
+					return err
+				}
+			}
+			// Catches go to the party while there is room; Pokemon sent
+			// to the box stay there
+			if encounter.Status == RunCaught {
+				if len(run.Party) < maxPartySize {
+					run.Party = append(run.Party, encounter.Area)
+				} else {
+					encounter.Status = RunBoxed
+				}
+			}
+		}
+		run.Encounters = append(run.Encounters, encounter)
+		return nil
+	})
+	if err != nil {
+		writeRunError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(run)
+}
+
+// UpdateRunEncounter handles PATCH /api/runs/{id}/encounters/{area}:
+// evolves, renames, boxes, withdraws or faints a Pokemon, or changes its
+// ability and moves. Fainted Pokemon and missed encounters cannot change
+// status.
+func UpdateRunEncounter(w http.ResponseWriter, r *http.Request, runs *RunStore, store *Store) {
+	run, cache, ok := runForRequest(w, r, runs, store)
+	if !ok {
+		return
+	}
+	_, area := runPath(r)
+	area = strings.ToLower(strings.TrimPrefix(area, "encounters/"))
+
+	var req RunEncounterUpdate
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	run, err := runs.Update(run.ID, func(run *Run) error {
+		e := run.Encounter(area)
+		if e == nil {
+			return &runError{status: http.StatusNotFound, msg: fmt.Sprintf("No encounter on %s in run %s", area, run.ID)}
+		}
+		return applyEncounterUpdate(run, e, req, cache)
+	})
+	if err != nil {
+		writeRunError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(run)
+}
+
+// SetRunParty handles PUT /api/runs/{id}/party: replaces the active party
+// with living Pokemon of the run, given by area. Living Pokemon left out are
+// boxed.
+func SetRunParty(w http.ResponseWriter, r *http.Request, runs *RunStore, store *Store) {
+	run, _, ok := runForRequest(w, r, runs, store)
+	if !ok {
+		return
+	}
+
+	var req RunPartyRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if len(req.Party) > maxPartySize {
+		writeRunError(w, invalidf("party can have at most %d Pokemon, got %d", maxPartySize, len(req.Party)))
+		return
+	}
+
+	run, err := runs.Update(run.ID, func(run *Run) error {
+		party := make([]string, 0, len(req.Party))
+		inParty := make(map[string]bool)
+		for i, area := range req.Party {
+			e := run.Encounter(strings.ToLower(strings.TrimSpace(area)))
+			switch {
+			case e == nil:
+				return invalidf("party[%d]: no encounter on %s in run %s", i, area, run.ID)
+			case !e.Alive():
+				return conflictf("party[%d]: %s from %s is %s", i, e.Pokemon, e.Area, e.Status)
+			case inParty[e.Area]:
+				return invalidf("party[%d]: %s is already in the party", i, e.Area)
+			}
+			inParty[e.Area] = true
+			party = append(party, e.Area)
+		}
+		for i := range run.Encounters {
+			e := &run.Encounters[i]
+			if inParty[e.Area] {
+				e.Status = RunCaught
+			} else if e.Alive() {
+				e.Status = RunBoxed
+			}
+		}
+		run.Party = party
+		return nil
+	})
+	if err != nil {
+		writeRunError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(run)
+}
+
+// runForRequest returns the run named in the path and the data of its game.
+// Unknown runs and games without loaded data are answered and ok is false.
+func runForRequest(w http.ResponseWriter, r *http.Request, runs *RunStore, store *Store) (*Run, *Cache, bool) {
+	id, _ := runPath(r)
+	run := runs.Get(id)
+	if run == nil {
+		writeRunError(w, errRunNotFound)
+		return nil, nil, false
+	}
+	cache := store.Cache(run.Game)
+	if cache == nil {
+		writeRunError(w, invalidf("no data loaded for %s", run.Game))
+		return nil, nil, false
+	}
+	return run, cache, true
+}
+
+// runPath splits /api/runs/{id}/{rest} into the run ID and the rest.
+func runPath(r *http.Request) (id, rest string) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/runs/"), "/")
+	id, rest, _ = strings.Cut(path, "/")
+	return id, rest
+}
+
+// writeRunError answers with the status of a runError, 404 for unknown runs
+// and 500 for anything else, such as a failed save.
+func writeRunError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	msg := "Could not save run: " + err.Error()
+	var re *runError
+	switch {
+	case errors.As(err, &re):
+		status, msg = re.status, re.msg
+	case errors.Is(err, errRunNotFound):
+		status, msg = http.StatusNotFound, "Run not found"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
+
+// resolveRunEncounter validates a new encounter against the game's encounter
+// areas and resolves its Pokemon, ability and moves. The requested status is
+// kept; a missing one means caught.
+func resolveRunEncounter(req RunEncounterRequest, cache *Cache) (RunEncounter, *Pokemon, error) {
+	if len(cache.Encounters) == 0 {
+		return RunEncounter{}, nil, invalidf("no encounter data loaded for %s; run -ingest to generate it", cache.Game.Name)
+	}
+	area := strings.ToLower(strings.TrimSpace(req.Area))
+	name := strings.ToLower(strings.TrimSpace(req.Pokemon))
+	if area == "" || name == "" {
+		return RunEncounter{}, nil, invalidf("area and pokemon are required")
+	}
+	pokemon := cache.lookupPokemon(name)
+	if pokemon == nil {
+		return RunEncounter{}, nil, &runError{status: http.StatusNotFound, msg: fmt.Sprintf("Pokemon not found: %s", name)}
+	}
+	area, err := resolveRunArea(area, pokemon, cache)
+	if err != nil {
+		return RunEncounter{}, nil, err
+	}
+
+	status := strings.ToLower(strings.TrimSpace(req.Status))
+	switch status {
+	case "":
+		status = RunCaught
+	case RunCaught, RunBoxed, RunFainted, RunMissed:
+	default:
+		return RunEncounter{}, nil, invalidf("invalid status %q (valid: %s, %s, %s, %s)", status, RunCaught, RunBoxed, RunFainted, RunMissed)
+	}
+
+	e := RunEncounter{
+		Area:     area,
+		Pokemon:  pokemon.Name,
+		Nickname: strings.TrimSpace(req.Nickname),
+		Status:   status,
+	}
+	if e.Alive() {
+		if e.Ability, err = resolveAbility(pokemon, req.Ability, cache); err != nil {
+			return RunEncounter{}, nil, invalidf("%s", err)
+		}
+		if e.Moves, err = resolveRunMoves(pokemon, req.Moves, cache); err != nil {
+			return RunEncounter{}, nil, err
+		}
+	}
+	return e, pokemon, nil
+}
+
+// resolveRunArea returns the name of the location area where a Pokemon was
+// met. The Pokemon must be found there in the wild, or be a gift received
+// there. The "-area" suffix may be left out.
+func resolveRunArea(area string, pokemon *Pokemon, cache *Cache) (string, error) {
+	encounters := cache.AreaEncounters(area)
+	if encounters == nil && cache.AreaEncounters(area+"-area") != nil {
+		area += "-area"
+		encounters = cache.AreaEncounters(area)
+	}
+	for _, enc := range encounters {
+		if enc.Pokemon == pokemon.Name {
+			return area, nil
+		}
+	}
+	if p := cache.Game.Progression(); p != nil {
+		for _, g := range p.Gifts {
+			if g.Location == area && g.Pokemon == pokemon.Name {
+				return area, nil
+			}
+		}
+	}
+	if encounters == nil {
+		return "", &runError{status: http.StatusNotFound, msg: fmt.Sprintf("Location area not found: %s", area)}
+	}
+	return "", invalidf("%s is not found in %s in %s", pokemon.Name, area, cache.Game.Name)
+}
+
+// resolveRunMoves validates the moves of a run's Pokemon like a team slot.
+func resolveRunMoves(pokemon *Pokemon, moves []string, cache *Cache) ([]string, error) {
+	if len(moves) > maxMovesPerMember {
+		return nil, invalidf("%s can have at most %d moves, got %d", pokemon.Name, maxMovesPerMember, len(moves))
+	}
+	var names []string
+	for _, query := range moves {
+		name, ok := cache.MoveNameIndex[strings.ToLower(strings.TrimSpace(query))]
+		if !ok {
+			return nil, invalidf("move not found: %s", query)
+		}
+		if !pokemon.LearnsMove(cache.Game.VersionGroup, name) {
+			return nil, invalidf("%s cannot learn %s in %s", pokemon.Name, name, cache.Game.Name)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// applyEncounterUpdate applies a PATCH to one of a run's encounters.
+func applyEncounterUpdate(run *Run, e *RunEncounter, req RunEncounterUpdate, cache *Cache) error {
+	pokemon := cache.PokemonByName(e.Pokemon)
+	if pokemon == nil {
+		return invalidf("Pokemon not found: %s", e.Pokemon)
+	}
+	if !e.Alive() && (req.Pokemon != nil || req.Ability != nil || req.Moves != nil) {
+		return conflictf("%s from %s is %s", e.Pokemon, e.Area, e.Status)
+	}
+
+	if req.Pokemon != nil {
+		evolved := cache.lookupPokemon(strings.ToLower(strings.TrimSpace(*req.Pokemon)))
+		if evolved == nil {
+			return &runError{status: http.StatusNotFound, msg: fmt.Sprintf("Pokemon not found: %s", *req.Pokemon)}
+		}
+		if runFamily(evolved, cache) != runFamily(pokemon, cache) {
+			return invalidf("%s is not in the evolution family of %s", evolved.Name, pokemon.Name)
+		}
+		if run.SpeciesClause {
+			if err := checkSpeciesClause(run, cache, evolved, e.Area); err != nil {
+				return err
+			}
+		}
+		if evolved.Name != pokemon.Name {
+			// Abilities and moves carry over only if the new form keeps them
+			if !evolved.HasAbility(e.Ability) {
+				e.Ability = evolved.DefaultAbility()
+			}
+			if _, err := resolveRunMoves(evolved, e.Moves, cache); err != nil {
+				e.Moves = nil
+			}
+		}
+		e.Pokemon, pokemon = evolved.Name, evolved
+	}
+	if req.Nickname != nil {
+		e.Nickname = strings.TrimSpace(*req.Nickname)
+	}
+	if req.Ability != nil {
+		ability, err := resolveAbility(pokemon, *req.Ability, cache)
+		if err != nil {
+			return invalidf("%s", err)
+		}
+		e.Ability = ability
+	}
+	if req.Moves != nil {
+		moves, err := resolveRunMoves(pokemon, *req.Moves, cache)
+		if err != nil {
+			return err
+		}
+		e.Moves = moves
+	}
+	if req.Status != nil {
+		return changeRunStatus(run, e, strings.ToLower(strings.TrimSpace(*req.Status)))
+	}
+	return nil
+}
+
+// changeRunStatus moves a living Pokemon between the party and the box, or
+// marks it fainted. Missed encounters are only recorded as such.
+func changeRunStatus(run *Run, e *RunEncounter, status string) error {
+	switch status {
+	case RunCaught, RunBoxed, RunFainted:
+	case RunMissed:
+		return invalidf("%s from %s was already caught", e.Pokemon, e.Area)
+	default:
+		return invalidf("invalid status %q (valid: %s, %s, %s)", status, RunCaught, RunBoxed, RunFainted)
+	}
+	if status == e.Status {
+		return nil
+	}
+	if !e.Alive() {
+		return conflictf("%s from %s is %s", e.Pokemon, e.Area, e.Status)
+	}
+
+	if status == RunCaught {
+		if len(run.Party) >= maxPartySize {
+			return conflictf("party is full; box a Pokemon first")
+		}
+		run.Party = append(run.Party, e.Area)
+	} else {
+		run.removeFromParty(e.Area)
+	}
+	e.Status = status
+	return nil
+}
+
+// checkDupesClause refuses an encounter whose evolution family was already
+// caught in the run, dead or alive.
+func checkDupesClause(run *Run, cache *Cache, pokemon *Pokemon) error {
+	family := runFamily(pokemon, cache)
+	for _, e := range run.Encounters {
+		if e.Status == RunMissed {
+			continue
+		}
+		if other := cache.PokemonByName(e.Pokemon); other != nil && runFamily(other, cache) == family {
+			return conflictf("dupes clause: the evolution family of %s was already caught on %s (%s); this encounter does not count", pokemon.Name, e.Area, e.Pokemon)
+		}
+	}
+	return nil
+}
+
+// checkSpeciesClause refuses a Pokemon whose species is already alive in the
+// run, not counting the encounter on skipArea.
+func checkSpeciesClause(run *Run, cache *Cache, pokemon *Pokemon, skipArea string) error {
+	species := speciesName(pokemon)
+	for _, e := range run.Encounters {
+		if e.Area == skipArea || !e.Alive() {
+			continue
+		}
+		if other := cache.PokemonByName(e.Pokemon); other != nil && speciesName(other) == species {
+			return conflictf("species clause: %s from %s is already alive", e.Pokemon, e.Area)
+		}
+	}
+	return nil
+}
+
+// runFamily identifies a Pokemon's evolution family by its evolution chain,
+// or by its species if the chain is not loaded.
+func runFamily(p *Pokemon, cache *Cache) string {
+	if chain := cache.EvolutionChain(p); chain != nil {
+		return fmt.Sprintf("chain-%d", chain.ID)
+	}
+	return speciesName(p)
+}
+
+// speciesName returns the species of a Pokemon, which is its own name for
+// data without species.
+func speciesName(p *Pokemon) string {
+	if p.Species.Name != "" {
+		return p.Species.Name
+	}
+	return p.Name
+}
+
+// runTeam restricts a team request to the living Pokemon of a run. An empty
+// team is the run's party with its recorded abilities and moves; otherwise
+// every slot must name a living Pokemon of the run, by name or nickname, and
+// each can be used once. Slots without ability or moves take the recorded
+// ones. A nil run leaves the team as is.
+func runTeam(team []TeamMemberRequest, run *Run, cache *Cache) ([]TeamMemberRequest, error) {
+	if run == nil {
+		return team, nil
+	}
+	if run.Game != cache.Game.Key {
+		return nil, fmt.Errorf("run %s is a %s run; select it with ?game=%s", run.ID, run.Game, run.Game)
+	}
+
+	if len(team) == 0 {
+		for _, area := range run.Party {
+			if e := run.Encounter(area); e != nil {
+				team = append(team, TeamMemberRequest{Pokemon: e.Pokemon, Ability: e.Ability, Moves: e.Moves})
+			}
+		}
+		if len(team) == 0 {
+			return nil, fmt.Errorf("run %s has no Pokemon in its party", run.ID)
+		}
+		return team, nil
+	}
+
+	alive := run.Alive()
+	used := make([]bool, len(alive))
+	restricted := make([]TeamMemberRequest, 0, len(team))
+	for i, slot := range team {
+		name := strings.ToLower(strings.TrimSpace(slot.Pokemon))
+		found := -1
+		for j, e := range alive {
+			if !used[j] && (e.Pokemon == name || (e.Nickname != "" && strings.EqualFold(e.Nickname, name))) {
+				found = j
+				break
+			}
+		}
+		if found < 0 {
+			return nil, fmt.Errorf("team[%d]: %s is not a living Pokemon of run %s", i, slot.Pokemon, run.ID)
+		}
+		used[found] = true
+
+		e := alive[found]
+		member := TeamMemberRequest{Pokemon: e.Pokemon, Ability: slot.Ability, Moves: slot.Moves}
+		if member.Ability == "" {
+			member.Ability = e.Ability
+		}
+		if len(member.Moves) == 0 {
+			member.Moves = e.Moves
+		}
+		restricted = append(restricted, member)
+	}
+	return restricted, nil
+}
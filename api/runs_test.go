@@ -0,0 +1,56 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAddRunEncounterSpeciesClause(t *testing.T) {
+	cache := testCache(t, rawData{
+		Pokemon: []map[string]interface{}{
+			testPokemon(gameOf(t), 194, "wooper", []string{"water", "ground"}),
+		},
+		Moves: []map[string]interface{}{testMove(1, "tackle", "normal", "physical", 35)},
+		Encounters: []map[string]interface{}{
+			testEncounter(gameOf(t), "route-32-area", "wooper"),
+			testEncounter(gameOf(t), "route-33-area", "wooper"),
+		},
+	})
+	store := testStore(cache)
+
+	tests := []struct {
+		speciesClause bool
+		wantStatus    int
+	}{
+		{speciesClause: true, wantStatus: http.StatusConflict},
+		{speciesClause: false, wantStatus: http.StatusCreated},
+	}
+	for _, tt := range tests {
+		runs, err := OpenRunStore(filepath.Join(t.TempDir(), "runs.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		run, err := runs.Create(&Run{Name: "test", Game: cache.Game.Key, SpeciesClause: tt.speciesClause})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i, area := range []string{"route-32", "route-33"} {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/api/runs/"+run.ID+"/encounters",
+				strings.NewReader(`{"area": "`+area+`", "pokemon": "wooper"}`))
+			AddRunEncounter(w, r, runs, store)
+
+			want := http.StatusCreated
+			if i == 1 {
+				want = tt.wantStatus
+			}
+			if w.Code != want {
+				t.Errorf("species_clause=%v: %s: got status %d, want %d: %s", tt.speciesClause, area, w.Code, want, w.Body)
+			}
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Statuses of a Nuzlocke encounter. A caught Pokemon is in the party and a
+// boxed one is alive in the PC; fainted Pokemon are dead for good and a
+// missed encounter fled or was knocked out, using up its route.
+const (
+	RunCaught  = "caught"
+	RunBoxed   = "boxed"
+	RunFainted = "fainted"
+	RunMissed  = "missed"
+)
+
+// maxPartySize is the number of Pokemon a run can carry.
+const maxPartySize = 6
+
+// errRunNotFound is returned by RunStore for unknown run IDs.
+var errRunNotFound = errors.New("run not found")
+
+// RunEncounter is the first encounter of a Nuzlocke run on a location area.
+type RunEncounter struct {
+	Area     string   `json:"area"`
+	Pokemon  string   `json:"pokemon"`
+	Nickname string   `json:"nickname,omitempty"`
+	Status   string   `json:"status"`
+	Ability  string   `json:"ability,omitempty"`
+	Moves    []string `json:"moves,omitempty"`
+}
+
+// Alive reports whether the encounter's Pokemon was caught and has not
+// fainted.
+func (e *RunEncounter) Alive() bool {
+	return e.Status == RunCaught || e.Status == RunBoxed
+}
+
+// Run is a Nuzlocke playthrough: one counting encounter per location area
+// and the active party, given as the areas of its members. DupesClause lets
+// encounters whose evolution family was already met be skipped;
+// SpeciesClause forbids two living Pokemon of the same species.
+type Run struct {
+	ID            string         `json:"id"`
+	Name          string         `json:"name"`
+	Game          string         `json:"game"`
+	DupesClause   bool           `json:"dupes_clause"`
+	SpeciesClause bool           `json:"species_clause"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	Encounters    []RunEncounter `json:"encounters"`
+	Party         []string       `json:"party"`
+}
+
+// Encounter returns the run's encounter on an area, or nil. The "-area"
+// suffix of PokeAPI area names may be left out.
+func (run *Run) Encounter(area string) *RunEncounter {
+	for i := range run.Encounters {
+		if run.Encounters[i].Area == area || run.Encounters[i].Area == area+"-area" {
+			return &run.Encounters[i]
+		}
+	}
+	return nil
+}
+
+// Alive returns the encounters whose Pokemon are alive, party first in
+// party order, then the boxed ones in encounter order.
+func (run *Run) Alive() []RunEncounter {
+	var alive []RunEncounter
+	for _, area := range run.Party {
+		if e := run.Encounter(area); e != nil {
+			alive = append(alive, *e)
+		}
+	}
+	for _, e := range run.Encounters {
+		if e.Status == RunBoxed {
+			alive = append(alive, e)
+		}
+	}
+	return alive
+}
+
+// removeFromParty takes an area out of the party.
+func (run *Run) removeFromParty(area string) {
+	party := run.Party[:0]
+	for _, a := range run.Party {
+		if a != area {
+			party = append(party, a)
+		}
+	}
+	run.Party = party
+}
+
+// clone returns a deep copy of the run.
+func (run *Run) clone() *Run {
+	c := *run
+	c.Encounters = make([]RunEncounter, len(run.Encounters))
+	for i, e := range run.Encounters {
+		e.Moves = append([]string(nil), e.Moves...)
+		c.Encounters[i] = e
+	}
+	c.Party = append([]string{}, run.Party...)
+	return &c
+}
+
+// RunStore keeps Nuzlocke runs in a local JSON file. Every change is written
+// to disk before it becomes visible.
+type RunStore struct {
+	path string
+	mu   sync.Mutex
+	runs map[string]*Run
+}
+
+// OpenRunStore loads the runs saved in path. A missing file is an empty
+// store; it is created on the first change.
+func OpenRunStore(path string) (*RunStore, error) {
+	s := &RunStore{path: path, runs: make(map[string]*Run)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+	var runs []*Run
+	if err := json.Unmarshal(data, &runs); err != nil {
+		return nil, fmt.Errorf("error decoding %s: %w", path, err)
+	}
+	for _, run := range runs {
+		s.runs[run.ID] = run
+	}
+	return s, nil
+}
+
+// Len returns the number of runs.
+func (s *RunStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.runs)
+}
+
+// List returns copies of every run, oldest first.
+func (s *RunStore) List() []*Run {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sorted(true)
+}
+
+// Get returns a copy of a run, or nil if it does not exist.
+func (s *RunStore) Get(id string) *Run {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if run, ok := s.runs[id]; ok {
+		return run.clone()
+	}
+	return nil
+}
+
+// Create assigns a new run its ID and timestamps and saves it.
+func (s *RunStore) Create(run *Run) (*Run, error) {
+	id, err := newRunID()
+	if err != nil {
+		return nil, err
+	}
+	run = run.clone()
+	run.ID = id
+	run.CreatedAt = time.Now().UTC()
+	run.UpdatedAt = run.CreatedAt
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runs[id] = run
+	if err := s.save(); err != nil {
+		delete(s.runs, id)
+		return nil, err
+	}
+	return run.clone(), nil
+}
+
+// Update applies fn to a copy of a run and saves the result. If fn or the
+// save fails the run is left unchanged.
+func (s *RunStore) Update(id string, fn func(run *Run) error) (*Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.runs[id]
+	if !ok {
+		return nil, errRunNotFound
+	}
+	run := old.clone()
+	if err := fn(run); err != nil {
+		return nil, err
+	}
+	run.UpdatedAt = time.Now().UTC()
+	s.runs[id] = run
+	if err := s.save(); err != nil {
+		s.runs[id] = old
+		return nil, err
+	}
+	return run.clone(), nil
+}
+
+// Delete removes a run.
+func (s *RunStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run, ok := s.runs[id]
+	if !ok {
+		return errRunNotFound
+	}
+	delete(s.runs, id)
+	if err := s.save(); err != nil {
+		s.runs[id] = run
+		return err
+	}
+	return nil
+}
+
+// ForRequest returns the run selected with ?run=, or nil if none is. Unknown
+// runs are answered with 404 and ok is false.
+func (s *RunStore) ForRequest(w http.ResponseWriter, r *http.Request) (run *Run, ok bool) {
+	id := strings.TrimSpace(r.URL.Query().Get("run"))
+	if id == "" {
+		return nil, true
+	}
+	if run = s.Get(id); run == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Run not found: %s", id)})
+		return nil, false
+	}
+	return run, true
+}
+
+// sorted returns the runs oldest first, copied if copies is set. The caller
+// must hold s.mu.
+func (s *RunStore) sorted(copies bool) []*Run {
+	runs := make([]*Run, 0, len(s.runs))
+	for _, run := range s.runs {
+		if copies {
+			run = run.clone()
+		}
+		runs = append(runs, run)
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		if !runs[i].CreatedAt.Equal(runs[j].CreatedAt) {
+			return runs[i].CreatedAt.Before(runs[j].CreatedAt)
+		}
+		return runs[i].ID < runs[j].ID
+	})
+	return runs
+}
+
+// save writes every run to a temporary file and renames it over the store's
+// file, so a crash never leaves it half written. The caller must hold s.mu.
+func (s *RunStore) save() error {
+	data, err := json.MarshalIndent(s.sorted(false), "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling runs: %w", err)
+	}
+	if dir := filepath.Dir(s.path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("error creating %s: %w", dir, err)
+		}
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("error writing %s: %w", tmp, err)
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// newRunID returns a random 12 character hex ID.
+func newRunID() (string, error) {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating run ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
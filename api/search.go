@@ -4,25 +4,25 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
+
+	"pokeproject/typeeffectiveness"
 )
 
 // SearchMatchItem represents a single Pokemon match in search results.
 type SearchMatchItem struct {
-	ID         int    `json:"id"`
-	Name       string `json:"name"`
-	RegionalID int    `json:"regional_id"`
-	Types      []struct {
-		Slot int `json:"slot"`
-		Type struct {
-			Name string `json:"name"`
-		} `json:"type"`
-	} `json:"types"`
-	Sprites struct {
+	ID         int           `json:"id"`
+	Name       string        `json:"name"`
+	RegionalID int           `json:"regional_id"`
+	Types      []PokemonType `json:"types"`
+	Sprites    struct {
 		FrontDefault string `json:"front_default"`
 	} `json:"sprites"`
 	MatchReason string `json:"match_reason"`
 	MatchedMove string `json:"matched_move,omitempty"`
+	FamilyID    int    `json:"family_id,omitempty"`
 }
 
 // SearchResults groups search matches by category.
@@ -32,19 +32,18 @@ type SearchResults struct {
 	ByMove []SearchMatchItem `json:"by_move"`
 }
 
-// SearchResponse is the top-level response for the search endpoint.
-type SearchResponse struct {
-	Query   string        `json:"query"`
-	Results SearchResults `json:"results"`
+// SearchFamily is an evolution family with at least one search match.
+type SearchFamily struct {
+	FamilyID int      `json:"family_id"`
+	Pokemon  []string `json:"pokemon"`
+	Matches  []string `json:"matches"`
 }
 
-// validTypes lists the 17 Gen IV types.
-var validTypes = map[string]bool{
-	"normal": true, "fire": true, "water": true, "electric": true,
-	"grass": true, "ice": true, "fighting": true, "poison": true,
-	"ground": true, "flying": true, "psychic": true, "bug": true,
-	"rock": true, "ghost": true, "dragon": true, "dark": true,
-	"steel": true,
+// SearchResponse is the top-level response for the search endpoint.
+type SearchResponse struct {
+	Query    string         `json:"query"`
+	Results  SearchResults  `json:"results"`
+	Families []SearchFamily `json:"families,omitempty"`
 }
 
 // typeTranslations maps translated type names to their English API name.
@@ -55,24 +54,30 @@ var typeTranslations = map[string]string{
 	"veneno": "poison", "tierra": "ground", "volador": "flying", "psíquico": "psychic",
 	"psiquico": "psychic", "bicho": "bug", "roca": "rock", "fantasma": "ghost",
 	"dragón": "dragon", "dragon": "dragon", "siniestro": "dark", "acero": "steel",
+	"hada": "fairy",
 	// English (identity)
 	"fire": "fire", "water": "water", "electric": "electric", "grass": "grass",
 	"ice": "ice", "fighting": "fighting", "poison": "poison", "ground": "ground",
 	"flying": "flying", "psychic": "psychic", "bug": "bug", "rock": "rock",
-	"ghost": "ghost", "dark": "dark", "steel": "steel",
+	"ghost": "ghost", "dark": "dark", "steel": "steel", "fairy": "fairy",
 }
 
-// resolveTypeQuery checks if the query matches a type name (in any language).
-// Returns the English type name or "" if not a type.
-func resolveTypeQuery(query string) string {
+// resolveTypeQuery checks if the query matches a type name (in any language)
+// that exists in the given chart. Returns the English type name or "" if not a type.
+func resolveTypeQuery(query string, chart *typeeffectiveness.Chart) string {
 	// Exact match
-	if apiType, ok := typeTranslations[query]; ok {
+	if apiType, ok := typeTranslations[query]; ok && chart.IsValidType(apiType) {
 		return apiType
 	}
 	return ""
 }
 
 // SearchCached handles GET /api/search?q={query} using in-memory cache.
+// Type matches are limited to the types of the chart selected with ?gen=,
+// which defaults to the generation of the selected game. With ?group=family
+// the matches are also grouped by evolution family. With ?badges= only the
+// Pokemon obtainable with that many badges match, and move matches need a
+// learn method available by then.
 func SearchCached(w http.ResponseWriter, r *http.Request, cache *Cache) {
 	if r.Method != http.MethodGet {
 		w.Header().Set("Content-Type", "application/json")
@@ -91,12 +96,28 @@ func SearchCached(w http.ResponseWriter, r *http.Request, cache *Cache) {
 		return
 	}
 
+	chart, err := getChart(r, cache.Game.Generation)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	filter, err := parseBadgeFilter(r, cache)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
 	// Check if query matches a type (in any language)
-	matchedType := resolveTypeQuery(query)
+	matchedType := resolveTypeQuery(query, chart)
 
 	// Find matching moves: search by API name AND by translated names
 	matchingMoves := make(map[string]bool)
-	for name := range cache.MovesRaw {
+	for name := range cache.Moves {
 		if strings.Contains(name, query) {
 			matchingMoves[name] = true
 		}
@@ -114,36 +135,62 @@ func SearchCached(w http.ResponseWriter, r *http.Request, cache *Cache) {
 	log.Printf("Search: query=%q, matchedType=%q, matchingMoves=%d",
 		query, matchedType, len(matchingMoves))
 
-	for _, data := range cache.PokemonRaw {
-		item := buildSearchMatchItem(data)
-
-		// Search by name
-		if strings.Contains(strings.ToLower(item.Name), query) {
+	// Search by name
+	for _, item := range cache.searchItems {
+		if strings.Contains(item.Name, query) && filter.pokemonAvailable(item.Name) {
 			match := item
 			match.MatchReason = "name"
 			byName = append(byName, match)
 		}
+	}
 
-		// Search by type (using resolved type name)
-		if matchedType != "" && pokemonHasType(item, matchedType) {
-			match := item
+	// Search by type (using resolved type name)
+	if matchedType != "" {
+		for _, p := range cache.PokemonByType(matchedType) {
+			if !filter.pokemonAvailable(p.Name) {
+				continue
+			}
+			match := cache.searchItems[cache.position[p]]
 			match.MatchReason = "type"
 			byType = append(byType, match)
 		}
+	}
+
+	// Search by move: union of the learners of every matching move, in load order
+	if len(matchingMoves) > 0 {
+		learners := make(map[*Pokemon]bool)
+		for moveName := range matchingMoves {
+			for _, p := range cache.MoveLearners(moveName) {
+				learners[p] = true
+			}
+		}
+		ordered := make([]*Pokemon, 0, len(learners))
+		for p := range learners {
+			ordered = append(ordered, p)
+		}
+		sort.Slice(ordered, func(i, j int) bool {
+			return cache.position[ordered[i]] < cache.position[ordered[j]]
+		})
 
-		// Search by move
-		if len(matchingMoves) > 0 {
-			if matchedMove := pokemonLearnsMoveInHGSS(data, matchingMoves); matchedMove != "" {
-				match := item
-				match.MatchReason = "move"
-				if moveData, ok := cache.MovesRaw[matchedMove]; ok {
-					lang := getLang(r)
-					match.MatchedMove = getTranslatedName(moveData, lang)
-				} else {
-					match.MatchedMove = matchedMove
+		lang := getLang(r)
+		for _, p := range ordered {
+			matchedMove := pokemonLearnsMove(p, cache.Game.VersionGroup, matchingMoves)
+			if filter != nil {
+				if !filter.pokemonAvailable(p.Name) {
+					continue
+				}
+				if matchedMove = filter.availableMove(p, cache, matchingMoves); matchedMove == "" {
+					continue
 				}
-				byMove = append(byMove, match)
 			}
+			match := cache.searchItems[cache.position[p]]
+			match.MatchReason = "move"
+			if move, ok := cache.Moves[matchedMove]; ok {
+				match.MatchedMove = move.DisplayName(lang)
+			} else {
+				match.MatchedMove = matchedMove
+			}
+			byMove = append(byMove, match)
 		}
 	}
 
@@ -156,6 +203,60 @@ func SearchCached(w http.ResponseWriter, r *http.Request, cache *Cache) {
 		},
 	}
 
+	if r.URL.Query().Get("group") == "family" {
+		resp.Families = groupFamilies(cache, byName, byType, byMove)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
 }
+
+// groupFamilies groups matched Pokemon by evolution family, in order of first
+// match. Each family lists its members from the regional Pokédex in evolution
+// order. Pokemon without evolution data form a family of their own.
+func groupFamilies(cache *Cache, results ...[]SearchMatchItem) []SearchFamily {
+	families := []SearchFamily{}
+	index := make(map[string]int) // family key -> position in families
+	matched := make(map[string]bool)
+	for _, items := range results {
+		for _, item := range items {
+			if matched[item.Name] {
+				continue
+			}
+			matched[item.Name] = true
+
+			key := item.Name
+			if item.FamilyID != 0 {
+				key = strconv.Itoa(item.FamilyID)
+			}
+			i, ok := index[key]
+			if !ok {
+				i = len(families)
+				index[key] = i
+				families = append(families, SearchFamily{
+					FamilyID: item.FamilyID,
+					Pokemon:  familyMembers(cache, item.Name),
+				})
+			}
+			families[i].Matches = append(families[i].Matches, item.Name)
+		}
+	}
+	return families
+}
+
+// familyMembers returns the Pokemon of the regional Pokédex in the evolution
+// family of a Pokemon, in evolution order.
+func familyMembers(cache *Cache, name string) []string {
+	p := cache.PokemonByName(name)
+	chain := cache.EvolutionChain(p)
+	if chain == nil {
+		return []string{name}
+	}
+	var members []string
+	for _, species := range chain.Species() {
+		for _, form := range cache.PokemonBySpecies(species) {
+			members = append(members, form.Name)
+		}
+	}
+	return members
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"pokeproject/stats"
+)
+
+// StatRange is the lowest and highest value of each stat at a level.
+type StatRange struct {
+	Min stats.Stats `json:"min"`
+	Max stats.Stats `json:"max"`
+}
+
+// PokemonStatsResponse is the API response for a Pokemon's in-game stats.
+type PokemonStatsResponse struct {
+	Pokemon string      `json:"pokemon"`
+	Level   int         `json:"level"`
+	Nature  string      `json:"nature"`
+	IVs     stats.Stats `json:"ivs"`
+	EVs     stats.Stats `json:"evs"`
+	Base    stats.Stats `json:"base"`
+	Stats   stats.Stats `json:"stats"`
+	Range   StatRange   `json:"range"`
+}
+
+// GetPokemonStatsCached handles GET /api/pokemon/{name}/stats.
+//
+// Query parameters: level (default 50), nature (default hardy), and ivs and
+// evs as one value for every stat or six comma-separated values in the order
+// hp,attack,defense,special-attack,special-defense,speed (default 31 IVs and
+// 0 EVs).
+func GetPokemonStatsCached(w http.ResponseWriter, r *http.Request, cache *Cache) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/pokemon/")
+	name := strings.ToLower(strings.TrimSuffix(path, "/stats"))
+
+	if name == "" {
+		http.Error(w, `{"error": "Pokemon name is required"}`, http.StatusBadRequest)
+		return
+	}
+
+	pokemon := cache.lookupPokemon(name)
+	if pokemon == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Pokemon not found: %s", name)})
+		return
+	}
+
+	resp, err := buildPokemonStats(pokemon, r.URL.Query().Get("level"), r.URL.Query().Get("nature"),
+		r.URL.Query().Get("ivs"), r.URL.Query().Get("evs"))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// buildPokemonStats parses and validates the stat parameters and computes the
+// Pokemon's stats.
+func buildPokemonStats(p *Pokemon, levelParam, natureParam, ivsParam, evsParam string) (PokemonStatsResponse, error) {
+	level := defaultBattleLevel
+	if levelParam != "" {
+		var err error
+		if level, err = strconv.Atoi(levelParam); err != nil {
+			return PokemonStatsResponse{}, fmt.Errorf("invalid level: %s", levelParam)
+		}
+	}
+	if err := stats.ValidateLevel(level); err != nil {
+		return PokemonStatsResponse{}, err
+	}
+
+	nature, err := stats.LookupNature(natureParam)
+	if err != nil {
+		return PokemonStatsResponse{}, err
+	}
+
+	ivs, err := stats.ParseSpread(ivsParam, stats.MaxIV)
+	if err != nil {
+		return PokemonStatsResponse{}, fmt.Errorf("invalid ivs: %v", err)
+	}
+	if err := stats.ValidateIVs(ivs); err != nil {
+		return PokemonStatsResponse{}, err
+	}
+
+	evs, err := stats.ParseSpread(evsParam, 0)
+	if err != nil {
+		return PokemonStatsResponse{}, fmt.Errorf("invalid evs: %v", err)
+	}
+	if err := stats.ValidateEVs(evs); err != nil {
+		return PokemonStatsResponse{}, err
+	}
+
+	base := p.BaseStats()
+	low, high := stats.Range(base, level)
+	return PokemonStatsResponse{
+		Pokemon: p.Name,
+		Level:   level,
+		Nature:  nature.Name,
+		IVs:     ivs,
+		EVs:     evs,
+		Base:    base,
+		Stats:   stats.Calculate(base, level, ivs, evs, nature),
+		Range:   StatRange{Min: low, Max: high},
+	}, nil
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"pokeproject/games"
+)
+
+// Store holds the datasets currently being served and replaces them atomically
+// when the data files are reloaded. Handlers should resolve their Cache once per
+// request so the whole request sees a consistent snapshot.
+type Store struct {
+	dataDir string
+	current atomic.Pointer[map[string]*Cache] // game key -> cache
+	mu      sync.Mutex                        // serializes reloads
+}
+
+// NewStore loads every game with data files in dataDir and returns a Store
+// serving them. The default game is required; other games are optional.
+func NewStore(dataDir string) (*Store, error) {
+	s := &Store{dataDir: dataDir}
+	caches, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	s.current.Store(&caches)
+	return s, nil
+}
+
+// load reads the data files of every available game.
+func (s *Store) load() (map[string]*Cache, error) {
+	caches := make(map[string]*Cache)
+	for _, game := range games.All {
+		if game.Key != games.DefaultKey {
+			if _, err := os.Stat(filepath.Join(s.dataDir, game.PokemonFile())); os.IsNotExist(err) {
+				continue
+			}
+		}
+		cache, err := NewCacheFromJSON(s.dataDir, game)
+		if err != nil {
+			return nil, err
+		}
+		caches[game.Key] = cache
+	}
+	return caches, nil
+}
+
+// Cache returns the current snapshot of a game's data, or nil if the game is not loaded.
+func (s *Store) Cache(gameKey string) *Cache {
+	return (*s.current.Load())[gameKey]
+}
+
+// Caches returns the loaded games' data in games.All order, all from the same
+// snapshot, so a concurrent reload cannot drop a game halfway through.
+func (s *Store) Caches() []*Cache {
+	var list []*Cache
+	caches := *s.current.Load()
+	for _, game := range games.All {
+		if cache, ok := caches[game.Key]; ok {
+			list = append(list, cache)
+		}
+	}
+	return list
+}
+
+// ForRequest returns the Cache of the game selected with ?game=, defaulting to
+// HG/SS. Unknown or unloaded games are answered with 400 and ok is false.
+func (s *Store) ForRequest(w http.ResponseWriter, r *http.Request) (cache *Cache, ok bool) {
+	game, err := games.Lookup(r.URL.Query().Get("game"))
+	if err == nil {
+		if cache = s.Cache(game.Key); cache == nil {
+			err = fmt.Errorf("no data loaded for %s", game.Name)
+		}
+	}
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return nil, false
+	}
+	return cache, true
+}
+
+// Reload reads the data files again and swaps every dataset if they are all
+// valid. On error the previous datasets keep being served.
+func (s *Store) Reload() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	caches, err := s.load()
+	if err != nil {
+		log.Printf("Reload failed, keeping previous data: %v", err)
+		return err
+	}
+	s.current.Store(&caches)
+	for key, cache := range caches {
+		log.Printf("Reload complete for %s: %d Pokemon, %d moves", key, len(cache.Pokemon), len(cache.Moves))
+	}
+	return nil
+}
+
+// dataFiles returns the files whose changes trigger a reload.
+func (s *Store) dataFiles() []string {
+	var files []string
+	for _, game := range games.All {
+		files = append(files,
+			filepath.Join(s.dataDir, game.PokemonFile()),
+			filepath.Join(s.dataDir, game.MovesFile()),
+			filepath.Join(s.dataDir, game.AbilitiesFile()),
+			filepath.Join(s.dataDir, game.SpeciesFile()),
+			filepath.Join(s.dataDir, game.EvolutionsFile()),
+			filepath.Join(s.dataDir, game.EncountersFile()),
+			filepath.Join(s.dataDir, game.ItemsFile()),
+			filepath.Join(s.dataDir, game.MachinesFile()),
+		)
+	}
+	return files
+}
+
+// fingerprint summarizes the size and modification time of the data files.
+func (s *Store) fingerprint() string {
+	var fp string
+	for _, path := range s.dataFiles() {
+		info, err := os.Stat(path)
+		if err != nil {
+			fp += path + ":missing;"
+			continue
+		}
+		fp += fmt.Sprintf("%s:%d:%d;", path, info.Size(), info.ModTime().UnixNano())
+	}
+	return fp
+}
+
+// Watch polls the data files every interval and reloads when they change. A
+// change is only picked up once the files have been stable for a full interval,
+// so a reload never reads a half-written file. Watch returns when stop is closed.
+func (s *Store) Watch(interval time.Duration, stop <-chan struct{}) {
+	loaded := s.fingerprint()
+	pending := loaded
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			fp := s.fingerprint()
+			if fp != pending {
+				// Still changing, wait for the next tick
+				pending = fp
+				continue
+			}
+			if fp == loaded {
+				continue
+			}
+			log.Printf("Data files changed in %s, reloading...", s.dataDir)
+			s.Reload()
+			// Failed reloads are not retried until the files change again
+			loaded = fp
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"pokeproject/typeeffectiveness"
+)
+
+const (
+	maxTeamSize       = 6
+	maxMovesPerMember = 4
+)
+
+// TeamMemberRequest is a single team slot in a team analysis request.
+// Ability is optional and defaults to the Pokemon's first regular ability.
+type TeamMemberRequest struct {
+	Pokemon string   `json:"pokemon"`
+	Ability string   `json:"ability,omitempty"`
+	Moves   []string `json:"moves"`
+}
+
+// TeamAnalysisRequest is the body of POST /api/team/analyze.
+type TeamAnalysisRequest struct {
+	Team []TeamMemberRequest `json:"team"`
+}
+
+// CoverageHit is a single move that is super effective against a type.
+type CoverageHit struct {
+	Pokemon       string  `json:"pokemon"`
+	Move          string  `json:"move"`
+	DisplayName   string  `json:"display_name"`
+	Effectiveness float64 `json:"effectiveness"`
+}
+
+// WeaknessCount counts how many team members are weak to or resist a type.
+type WeaknessCount struct {
+	Weak   int `json:"weak"`
+	Resist int `json:"resist"`
+}
+
+// TeamAnalysisResponse is the API response for a team analysis.
+// Coverage and Weaknesses use the same shape as the frontend's
+// CoverageResult and WeaknessResult.
+type TeamAnalysisResponse struct {
+	Types           []string                 `json:"types"`
+	Coverage        map[string]int           `json:"coverage"`
+	CoverageDetails map[string][]CoverageHit `json:"coverage_details"`
+	Weaknesses      map[string]WeaknessCount `json:"weaknesses"`
+}
+
+// analyzedMove is a resolved move of a team member.
+type analyzedMove struct {
+	Name        string
+	DisplayName string
+	Type        string
+	DamageClass string
+}
+
+// analyzedMember is a resolved team member ready for analysis.
+type analyzedMember struct {
+	Name    string
+	Types   []string
+	Ability string
+	Moves   []analyzedMove
+}
+
+// AnalyzeTeamCached handles POST /api/team/analyze using the in-memory cache.
+// The type chart is selected with ?gen= and defaults to the game's generation.
+// With a Nuzlocke run selected with ?run=, the team is restricted to the
+// run's living Pokemon and an empty team is its party.
+func AnalyzeTeamCached(w http.ResponseWriter, r *http.Request, cache *Cache, run *Run) {
+	var req TeamAnalysisRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	chart, err := getChart(r, cache.Game.Generation)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	team, err := runTeam(req.Team, run, cache)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	members, err := resolveTeam(team, cache, getLang(r))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	resp := analyzeTeam(chart, members)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// resolveTeam validates a team request against the cache and resolves
+// each Pokemon's types and ability and each move's type and damage class.
+func resolveTeam(team []TeamMemberRequest, cache *Cache, lang string) ([]analyzedMember, error) {
+	if len(team) == 0 {
+		return nil, fmt.Errorf("team must contain at least one Pokemon")
+	}
+	if len(team) > maxTeamSize {
+		return nil, fmt.Errorf("team can have at most %d Pokemon, got %d", maxTeamSize, len(team))
+	}
+
+	var members []analyzedMember
+	for i, slot := range team {
+		name := strings.ToLower(strings.TrimSpace(slot.Pokemon))
+		if name == "" {
+			return nil, fmt.Errorf("team[%d]: pokemon is required", i)
+		}
+		if len(slot.Moves) > maxMovesPerMember {
+			return nil, fmt.Errorf("team[%d]: %s can have at most %d moves, got %d", i, name, maxMovesPerMember, len(slot.Moves))
+		}
+
+		pokemon := cache.PokemonByName(name)
+		if pokemon == nil {
+			return nil, fmt.Errorf("team[%d]: Pokemon not found: %s", i, name)
+		}
+
+		ability, err := resolveAbility(pokemon, slot.Ability, cache)
+		if err != nil {
+			return nil, fmt.Errorf("team[%d]: %w", i, err)
+		}
+
+		member := analyzedMember{Name: name, Types: pokemon.TypeNames(), Ability: ability}
+		for _, moveQuery := range slot.Moves {
+			moveName, ok := cache.MoveNameIndex[strings.ToLower(strings.TrimSpace(moveQuery))]
+			if !ok {
+				return nil, fmt.Errorf("team[%d]: move not found: %s", i, moveQuery)
+			}
+			if !pokemon.LearnsMove(cache.Game.VersionGroup, moveName) {
+				return nil, fmt.Errorf("team[%d]: %s cannot learn %s in %s", i, name, moveName, cache.Game.Name)
+			}
+
+			move := cache.Moves[moveName]
+			member.Moves = append(member.Moves, analyzedMove{
+				Name:        moveName,
+				DisplayName: move.DisplayName(lang),
+				Type:        move.Type.Name,
+				DamageClass: move.DamageClass.Name,
+			})
+		}
+
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+// analyzeTeam computes offensive coverage and defensive weaknesses for a team.
+// Coverage counts damaging moves that are super effective against each type;
+// weaknesses count members taking more or less than neutral damage from each type,
+// after their abilities (e.g. Levitate, Wonder Guard) are applied.
+func analyzeTeam(chart *typeeffectiveness.Chart, members []analyzedMember) TeamAnalysisResponse {
+	resp := TeamAnalysisResponse{
+		Types:           chart.Types,
+		Coverage:        make(map[string]int, len(chart.Types)),
+		CoverageDetails: make(map[string][]CoverageHit, len(chart.Types)),
+		Weaknesses:      make(map[string]WeaknessCount, len(chart.Types)),
+	}
+
+	for _, t := range chart.Types {
+		resp.Coverage[t] = 0
+		resp.CoverageDetails[t] = []CoverageHit{}
+		resp.Weaknesses[t] = WeaknessCount{}
+	}
+
+	for _, member := range members {
+		for _, move := range member.Moves {
+			if move.DamageClass != "physical" && move.DamageClass != "special" {
+				continue
+			}
+			for _, defType := range chart.Types {
+				factor := chart.GetEffectiveness(move.Type, defType)
+				if factor > 1.0 {
+					resp.Coverage[defType]++
+					resp.CoverageDetails[defType] = append(resp.CoverageDetails[defType], CoverageHit{
+						Pokemon:       member.Name,
+						Move:          move.Name,
+						DisplayName:   move.DisplayName,
+						Effectiveness: factor,
+					})
+				}
+			}
+		}
+
+		for _, atkType := range chart.Types {
+			factor := chart.GetEffectivenessWithAbility(atkType, member.Types, member.Ability)
+			count := resp.Weaknesses[atkType]
+			if factor > 1.0 {
+				count.Weak++
+			} else if factor < 1.0 {
+				count.Resist++
+			}
+			resp.Weaknesses[atkType] = count
+		}
+	}
+
+	return resp
+}
+
+// resolveAbility returns the ability a Pokemon is assumed to have: the
+// requested one, which may be given by translated name and must be one the
+// Pokemon can have, or else its first regular ability.
+func resolveAbility(pokemon *Pokemon, requested string, cache *Cache) (string, error) {
+	requested = strings.ToLower(strings.TrimSpace(requested))
+	if requested == "" {
+		return pokemon.DefaultAbility(), nil
+	}
+	name := requested
+	if ability := cache.lookupAbility(requested); ability != nil {
+		name = ability.Name
+	}
+	if !pokemon.HasAbility(name) {
+		return "", fmt.Errorf("%s cannot have ability %s", pokemon.Name, requested)
+	}
+	return name, nil
+}
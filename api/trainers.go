@@ -0,0 +1,252 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"pokeproject/games"
+	"pokeproject/typeeffectiveness"
+)
+
+// TrainerSummary is a trainer in the trainer list.
+type TrainerSummary struct {
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	Class         string   `json:"class"`
+	Location      string   `json:"location"`
+	Badge         string   `json:"badge,omitempty"`
+	PlayerStarter string   `json:"player_starter,omitempty"`
+	MaxLevel      int      `json:"max_level"`
+	Pokemon       []string `json:"pokemon"`
+	Types         []string `json:"types"`
+}
+
+// TrainerMove is a move of a trainer's Pokemon. Type and DamageClass are
+// empty if the move is not in the game's data.
+type TrainerMove struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Type        string `json:"type,omitempty"`
+	DamageClass string `json:"damage_class,omitempty"`
+	Power       *int   `json:"power,omitempty"`
+}
+
+// TrainerMember is a Pokemon of a trainer's team.
+type TrainerMember struct {
+	Pokemon         string        `json:"pokemon"`
+	Level           int           `json:"level"`
+	Types           []string      `json:"types"`
+	Ability         string        `json:"ability,omitempty"`
+	Item            string        `json:"item,omitempty"`
+	ItemDisplayName string        `json:"item_display_name,omitempty"`
+	Moves           []TrainerMove `json:"moves"`
+}
+
+// TrainerTypeSummary describes a trainer's team by type: how many Pokemon
+// have each type, how many damaging moves of each type it uses, and how the
+// team fares against each attack type. SharedWeaknesses lists the attack
+// types at least half the team is weak to, most common first.
+type TrainerTypeSummary struct {
+	PokemonTypes     map[string]int           `json:"pokemon_types"`
+	MoveTypes        map[string]int           `json:"move_types"`
+	Coverage         map[string]int           `json:"coverage"`
+	Weaknesses       map[string]WeaknessCount `json:"weaknesses"`
+	SharedWeaknesses []string                 `json:"shared_weaknesses"`
+}
+
+// TrainerResponse is the API response for a single trainer.
+type TrainerResponse struct {
+	TrainerSummary
+	Team    []TrainerMember    `json:"team"`
+	Summary TrainerTypeSummary `json:"summary"`
+}
+
+// GetTrainersCached handles GET /api/trainers: the important trainers of the
+// selected game, optionally filtered by ?class= (gym-leader, elite-four,
+// champion, rival...) and, for rival battles, by the player's ?starter=.
+func GetTrainersCached(w http.ResponseWriter, r *http.Request, cache *Cache) {
+	query := r.URL.Query()
+	class := strings.ToLower(strings.TrimSpace(query.Get("class")))
+	starter := strings.ToLower(strings.TrimSpace(query.Get("starter")))
+
+	list := []TrainerSummary{}
+	for _, t := range cache.Trainers {
+		if class != "" && t.Class != class {
+			continue
+		}
+		if starter != "" && t.PlayerStarter != "" && t.PlayerStarter != starter {
+			continue
+		}
+		list = append(list, buildTrainerSummary(t))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// GetTrainerCached handles GET /api/trainers/{id}: a trainer's team with
+// move details and a type summary computed with the chart selected with
+// ?gen= (the game's generation by default).
+func GetTrainerCached(w http.ResponseWriter, r *http.Request, cache *Cache) {
+	id := strings.ToLower(strings.TrimPrefix(r.URL.Path, "/api/trainers/"))
+	if id == "" {
+		http.Error(w, `{"error": "Trainer ID is required"}`, http.StatusBadRequest)
+		return
+	}
+
+	trainer := cache.Trainer(id)
+	if trainer == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Trainer not found: %s", id)})
+		return
+	}
+
+	chart, err := getChart(r, cache.Game.Generation)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	lang := getLang(r)
+	resp := TrainerResponse{
+		TrainerSummary: buildTrainerSummary(*trainer),
+		Team:           buildTrainerTeam(*trainer, cache, lang),
+		Summary:        buildTrainerTypeSummary(chart, trainerMembers(*trainer, cache, lang)),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// buildTrainerSummary lists a trainer's Pokemon and the distinct types of
+// its team, in team order.
+func buildTrainerSummary(t games.Trainer) TrainerSummary {
+	summary := TrainerSummary{
+		ID:            t.ID,
+		Name:          t.Name,
+		Class:         t.Class,
+		Location:      t.Location,
+		Badge:         t.Badge,
+		PlayerStarter: t.PlayerStarter,
+		MaxLevel:      t.MaxLevel(),
+		Pokemon:       []string{},
+		Types:         []string{},
+	}
+	seen := make(map[string]bool)
+	for _, p := range t.Team {
+		summary.Pokemon = append(summary.Pokemon, p.Pokemon)
+		for _, typ := range p.Types {
+			if !seen[typ] {
+				seen[typ] = true
+				summary.Types = append(summary.Types, typ)
+			}
+		}
+	}
+	return summary
+}
+
+// buildTrainerTeam resolves the abilities, held items and moves of a
+// trainer's team against the cache.
+func buildTrainerTeam(t games.Trainer, cache *Cache, lang string) []TrainerMember {
+	team := make([]TrainerMember, 0, len(t.Team))
+	for _, p := range t.Team {
+		member := TrainerMember{
+			Pokemon: p.Pokemon,
+			Level:   p.Level,
+			Types:   p.Types,
+			Ability: trainerPokemonAbility(p, cache),
+			Item:    p.Item,
+			Moves:   []TrainerMove{},
+		}
+		if item := cache.Items[p.Item]; item != nil {
+			member.ItemDisplayName = item.DisplayName(lang)
+		}
+		for _, name := range p.Moves {
+			move := TrainerMove{Name: name, DisplayName: name}
+			if m, ok := cache.Moves[name]; ok {
+				move.DisplayName = m.DisplayName(lang)
+				move.Type = m.Type.Name
+				move.DamageClass = m.DamageClass.Name
+				move.Power = m.Power
+			}
+			member.Moves = append(member.Moves, move)
+		}
+		team = append(team, member)
+	}
+	return team
+}
+
+// trainerMembers resolves a trainer's team for analysis. Moves missing from
+// the cache are left out.
+func trainerMembers(t games.Trainer, cache *Cache, lang string) []analyzedMember {
+	members := make([]analyzedMember, 0, len(t.Team))
+	for _, p := range t.Team {
+		member := analyzedMember{
+			Name:    p.Pokemon,
+			Types:   p.Types,
+			Ability: trainerPokemonAbility(p, cache),
+		}
+		for _, name := range p.Moves {
+			move, ok := cache.Moves[name]
+			if !ok {
+				continue
+			}
+			member.Moves = append(member.Moves, analyzedMove{
+				Name:        name,
+				DisplayName: move.DisplayName(lang),
+				Type:        move.Type.Name,
+				DamageClass: move.DamageClass.Name,
+			})
+		}
+		members = append(members, member)
+	}
+	return members
+}
+
+// trainerPokemonAbility returns the first regular ability of a trainer's
+// Pokemon, or "" if it is not in the cache.
+func trainerPokemonAbility(p games.TrainerPokemon, cache *Cache) string {
+	if pokemon := cache.PokemonByName(p.Pokemon); pokemon != nil {
+		return pokemon.DefaultAbility()
+	}
+	return ""
+}
+
+// buildTrainerTypeSummary computes a trainer's type summary with the same
+// coverage and weakness counts as a team analysis.
+func buildTrainerTypeSummary(chart *typeeffectiveness.Chart, members []analyzedMember) TrainerTypeSummary {
+	analysis := analyzeTeam(chart, members)
+	summary := TrainerTypeSummary{
+		PokemonTypes:     make(map[string]int),
+		MoveTypes:        make(map[string]int),
+		Coverage:         analysis.Coverage,
+		Weaknesses:       analysis.Weaknesses,
+		SharedWeaknesses: []string{},
+	}
+	for _, m := range members {
+		for _, t := range m.Types {
+			summary.PokemonTypes[t]++
+		}
+		for _, move := range m.Moves {
+			if move.DamageClass == "physical" || move.DamageClass == "special" {
+				summary.MoveTypes[move.Type]++
+			}
+		}
+	}
+
+	for _, t := range chart.Types {
+		if weak := analysis.Weaknesses[t].Weak; weak > 0 && weak*2 >= len(members) {
+			summary.SharedWeaknesses = append(summary.SharedWeaknesses, t)
+		}
+	}
+	sort.SliceStable(summary.SharedWeaknesses, func(i, j int) bool {
+		return analysis.Weaknesses[summary.SharedWeaknesses[i]].Weak > analysis.Weaknesses[summary.SharedWeaknesses[j]].Weak
+	})
+	return summary
+}
@@ -3,13 +3,78 @@ package api
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
 
 	"pokeproject/typeeffectiveness"
 )
 
-// GetTypeEffectiveness returns the Gen IV type effectiveness chart as JSON.
+// DefenderEffectivenessResponse is the API response for a single defender's
+// type combination.
+type DefenderEffectivenessResponse struct {
+	Defender    []string           `json:"defender"`
+	Ability     string             `json:"ability,omitempty"`
+	Multipliers map[string]float64 `json:"multipliers"`
+}
+
+// CombinationMatrixResponse is the API response for the full attack type vs
+// type combination matrix.
+type CombinationMatrixResponse struct {
+	Types        []string                      `json:"types"`
+	Combinations []string                      `json:"combinations"`
+	Matrix       map[string]map[string]float64 `json:"matrix"`
+}
+
+// GetTypeEffectiveness returns the type effectiveness chart of the generation
+// selected with ?gen= (Gen IV by default) as JSON.
+//
+// With ?defender=water,ground it returns the multiplier of every attack type
+// against that type combination instead, adjusted for ?ability= if given. With ?matrix=true it returns every
+// attack type against every single and dual type combination.
 func GetTypeEffectiveness(w http.ResponseWriter, r *http.Request) {
-	chart := typeeffectiveness.NewChart()
+	chart, err := getChart(r, typeeffectiveness.DefaultGeneration)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	query := r.URL.Query()
+
+	if defender := query.Get("defender"); defender != "" {
+		var defenseTypes []string
+		for _, t := range strings.Split(defender, ",") {
+			defenseTypes = append(defenseTypes, strings.ToLower(strings.TrimSpace(t)))
+		}
+		if err := chart.ValidateDefenseTypes(defenseTypes); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+
+		ability := strings.ToLower(strings.TrimSpace(query.Get("ability")))
+		resp := DefenderEffectivenessResponse{
+			Defender:    defenseTypes,
+			Ability:     ability,
+			Multipliers: chart.DefensiveProfileWithAbility(defenseTypes, ability),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	if query.Get("matrix") == "true" {
+		resp := CombinationMatrixResponse{
+			Types:  chart.Types,
+			Matrix: chart.CombinationMatrix(),
+		}
+		for _, combo := range chart.Combinations() {
+			resp.Combinations = append(resp.Combinations, typeeffectiveness.CombinationKey(combo))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(chart)
@@ -0,0 +1,20 @@
+package damage
+
+// defenderAbilityModifiers lists the defender abilities that change the
+// damage of a type through the attacker's stat or the move's power, as they
+// do in Generation IV. Immunities are type matchups and are left to
+// typeeffectiveness.
+var defenderAbilityModifiers = map[string][]Modifier{
+	"thick-fat": {
+		{Kind: ModifierAttack, Type: "fire", Multiplier: 0.5},
+		{Kind: ModifierAttack, Type: "ice", Multiplier: 0.5},
+	},
+	"heatproof": {{Kind: ModifierType, Type: "fire", Multiplier: 0.5}},
+	"dry-skin":  {{Kind: ModifierType, Type: "fire", Multiplier: 1.25}},
+}
+
+// DefenderAbilityModifiers returns the modifiers a defender's ability applies
+// to moves used against it, or nil if it has none.
+func DefenderAbilityModifiers(ability string) []Modifier {
+	return defenderAbilityModifiers[ability]
+}
@@ -0,0 +1,131 @@
+// Package damage implements the Generation IV damage formula.
+package damage
+
+import "errors"
+
+// Roll bounds of the random factor, as a percentage.
+const (
+	MinRoll = 85
+	MaxRoll = 100
+)
+
+// CritMultiplier is the damage multiplier of a critical hit in Gen IV.
+const CritMultiplier = 2
+
+// CritChance is the chance of a critical hit without boosting items or abilities.
+const CritChance = 1.0 / 16
+
+// Params are the inputs of a single damage calculation. Attack and Defense are
+// the final in-battle stats matching the move's damage class.
+type Params struct {
+	Level   int
+	Power   int
+	Attack  int
+	Defense int
+	// STAB is set when the move's type matches one of the attacker's types.
+	STAB bool
+	// Effectiveness holds the type multiplier against each of the defender's
+	// types, applied one after the other as the games do.
+	Effectiveness []float64
+	Critical      bool
+	Burned        bool
+	// Physical reports whether the move is physical, which is what burn halves.
+	Physical bool
+	// PreRandom are multipliers applied after a critical hit and before the
+	// random roll, such as Life Orb.
+	PreRandom []float64
+	// Modifiers are multipliers applied last, after type effectiveness, such
+	// as Expert Belt. A modifier of 0 blocks the hit.
+	Modifiers []float64
+}
+
+// Result is the damage of every random roll, from lowest to highest.
+type Result struct {
+	Rolls []int `json:"rolls"`
+	Min   int   `json:"min"`
+	Max   int   `json:"max"`
+}
+
+// Calculate returns the damage of every random roll for the given parameters.
+//
+// It follows the Gen IV formula and rounds down after each step:
+//
+//	(((2L/5+2) * Power * A/50) / D * Burn + 2) * Crit * PreRandom * R/100 * STAB * Type1 * Type2 * Modifiers
+//
+// Abilities and items that change the attacking stat or the move's power in
+// Gen IV, such as Thick Fat or Heatproof, must already be applied to Attack
+// and Power.
+//
+// Damage is at least 1 unless the defender is immune.
+func Calculate(p Params) (Result, error) {
+	if p.Level < 1 || p.Level > 100 {
+		return Result{}, errors.New("level must be between 1 and 100")
+	}
+	if p.Power <= 0 {
+		return Result{}, errors.New("power must be positive")
+	}
+	if p.Attack <= 0 || p.Defense <= 0 {
+		return Result{}, errors.New("attack and defense must be positive")
+	}
+
+	immune := false
+	for _, e := range append(append([]float64{}, p.Effectiveness...), p.Modifiers...) {
+		if e == 0 {
+			immune = true
+		}
+	}
+
+	base := (2*p.Level/5 + 2) * p.Power * p.Attack / 50 / p.Defense
+	if p.Burned && p.Physical {
+		base /= 2
+	}
+	base += 2
+	if p.Critical {
+		base *= CritMultiplier
+	}
+	for _, m := range p.PreRandom {
+		base = int(float64(base) * m)
+	}
+
+	var res Result
+	for roll := MinRoll; roll <= MaxRoll; roll++ {
+		dmg := base * roll / 100
+		if p.STAB {
+			dmg = dmg * 3 / 2
+		}
+		for _, e := range p.Effectiveness {
+			dmg = int(float64(dmg) * e)
+		}
+		for _, m := range p.Modifiers {
+			dmg = int(float64(dmg) * m)
+		}
+		if immune {
+			dmg = 0
+		} else if dmg < 1 {
+			dmg = 1
+		}
+		res.Rolls = append(res.Rolls, dmg)
+	}
+	res.Min = res.Rolls[0]
+	res.Max = res.Rolls[len(res.Rolls)-1]
+	return res, nil
+}
+
+// specialTypes are the types whose moves were special before the
+// physical/special split of Gen IV.
+var specialTypes = map[string]bool{
+	"fire": true, "water": true, "grass": true, "electric": true,
+	"ice": true, "psychic": true, "dragon": true, "dark": true,
+}
+
+// Category returns the damage class a damaging move uses in a generation. From
+// Gen IV on this is the move's own class; before that it depends on the type.
+func Category(generation int, moveType, damageClass string) string {
+	if generation >= 4 || damageClass == "status" {
+		return damageClass
+	}
+	if specialTypes[moveType] {
+		return "special"
+	}
+	return "physical"
+}
@@ -0,0 +1,89 @@
+package damage
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCalculate(t *testing.T) {
+	tests := []struct {
+		name   string
+		params Params
+		want   []int
+	}{
+		{
+			// Level 75 Glaceon (123 Atk) Ice Fang against Garchomp (163 Def)
+			name:   "stab-4x",
+			params: Params{Level: 75, Power: 65, Attack: 123, Defense: 163, STAB: true, Effectiveness: []float64{2, 2}},
+			want:   []int{168, 168, 168, 172, 172, 172, 180, 180, 180, 184, 184, 184, 192, 192, 192, 196},
+		},
+		{
+			name:   "neutral",
+			params: Params{Level: 50, Power: 80, Attack: 105, Defense: 95, Effectiveness: []float64{1}},
+			want:   []int{34, 34, 34, 35, 35, 36, 36, 36, 37, 37, 38, 38, 38, 39, 39, 40},
+		},
+		{
+			// Life Orb is applied before the random roll, not with the final modifiers
+			name: "life-orb",
+			params: Params{Level: 100, Power: 120, Attack: 359, Defense: 251, STAB: true,
+				Effectiveness: []float64{2}, PreRandom: []float64{1.3}},
+			want: []int{480, 486, 492, 498, 504, 510, 512, 518, 524, 530, 536, 542, 548, 554, 560, 566},
+		},
+		{
+			name: "final-modifier",
+			params: Params{Level: 100, Power: 120, Attack: 359, Defense: 251, STAB: true,
+				Effectiveness: []float64{2}, Modifiers: []float64{1.3}},
+			want: []int{483, 486, 494, 499, 501, 509, 514, 522, 525, 533, 538, 546, 548, 556, 561, 569},
+		},
+		{
+			name: "burned-critical",
+			params: Params{Level: 50, Power: 70, Attack: 120, Defense: 100, Effectiveness: []float64{0.5},
+				Critical: true, Burned: true, Physical: true},
+			want: []int{17, 17, 17, 17, 17, 18, 18, 18, 18, 18, 19, 19, 19, 19, 19, 20},
+		},
+		{
+			// Burn only halves physical moves
+			name: "burned-special",
+			params: Params{Level: 50, Power: 80, Attack: 105, Defense: 95, Effectiveness: []float64{1},
+				Burned: true},
+			want: []int{34, 34, 34, 35, 35, 36, 36, 36, 37, 37, 38, 38, 38, 39, 39, 40},
+		},
+		{
+			name:   "at-least-1",
+			params: Params{Level: 1, Power: 10, Attack: 5, Defense: 300, Effectiveness: []float64{0.25}},
+			want:   []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+		},
+		{
+			name:   "immune",
+			params: Params{Level: 50, Power: 100, Attack: 200, Defense: 100, Effectiveness: []float64{1}, Modifiers: []float64{0}},
+			want:   []int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+		},
+	}
+	for _, tt := range tests {
+		got, err := Calculate(tt.params)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got.Rolls, tt.want) {
+			t.Errorf("%s: got rolls %v, want %v", tt.name, got.Rolls, tt.want)
+		}
+		if got.Min != tt.want[0] || got.Max != tt.want[len(tt.want)-1] {
+			t.Errorf("%s: got %d-%d, want %d-%d", tt.name, got.Min, got.Max, tt.want[0], tt.want[len(tt.want)-1])
+		}
+	}
+}
+
+func TestCalculateInvalid(t *testing.T) {
+	for _, p := range []Params{
+		{Level: 0, Power: 80, Attack: 100, Defense: 100},
+		{Level: 101, Power: 80, Attack: 100, Defense: 100},
+		{Level: 50, Power: 0, Attack: 100, Defense: 100},
+		{Level: 50, Power: 80, Attack: 0, Defense: 100},
+		{Level: 50, Power: 80, Attack: 100, Defense: 0},
+	} {
+		if _, err := Calculate(p); err == nil {
+			t.Errorf("%+v: got no error", p)
+		}
+	}
+}
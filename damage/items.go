@@ -0,0 +1,135 @@
+package damage
+
+// Kinds of item modifiers.
+const (
+	// ModifierStat multiplies one of the holder's stats.
+	ModifierStat = "stat"
+	// ModifierType multiplies the power of the holder's moves of one type.
+	ModifierType = "type"
+	// ModifierCategory multiplies the power of the holder's physical or special moves.
+	ModifierCategory = "category"
+	// ModifierBaseDamage multiplies the damage of the holder's moves before
+	// the random roll.
+	ModifierBaseDamage = "base-damage"
+	// ModifierDamage multiplies the final damage of the holder's moves.
+	ModifierDamage = "damage"
+	// ModifierAttack multiplies the attacking stat of moves of one type used
+	// against the holder.
+	ModifierAttack = "attack"
+)
+
+// Modifier is a battle effect of a held item, as of Generation IV.
+type Modifier struct {
+	Kind        string  `json:"kind"`
+	Stat        string  `json:"stat,omitempty"`
+	Type        string  `json:"type,omitempty"`
+	DamageClass string  `json:"damage_class,omitempty"`
+	Multiplier  float64 `json:"multiplier"`
+	// SuperEffectiveOnly limits a damage modifier to super effective hits.
+	SuperEffectiveOnly bool `json:"super_effective_only,omitempty"`
+	// Species limits the modifier to these holders.
+	Species []string `json:"species,omitempty"`
+}
+
+// AppliesTo reports whether the modifier works for a holder species.
+func (m Modifier) AppliesTo(species string) bool {
+	if len(m.Species) == 0 {
+		return true
+	}
+	for _, s := range m.Species {
+		if s == species {
+			return true
+		}
+	}
+	return false
+}
+
+func statBoost(stat string, multiplier float64, species ...string) Modifier {
+	return Modifier{Kind: ModifierStat, Stat: stat, Multiplier: multiplier, Species: species}
+}
+
+func typeBoost(moveType string, species ...string) Modifier {
+	return Modifier{Kind: ModifierType, Type: moveType, Multiplier: 1.2, Species: species}
+}
+
+// itemModifiers lists the held items with a direct effect on damage.
+var itemModifiers = map[string][]Modifier{
+	"choice-band":    {statBoost("attack", 1.5)},
+	"choice-specs":   {statBoost("special-attack", 1.5)},
+	"choice-scarf":   {statBoost("speed", 1.5)},
+	"light-ball":     {statBoost("attack", 2, "pikachu"), statBoost("special-attack", 2, "pikachu")},
+	"thick-club":     {statBoost("attack", 2, "cubone", "marowak")},
+	"deep-sea-tooth": {statBoost("special-attack", 2, "clamperl")},
+	"deep-sea-scale": {statBoost("special-defense", 2, "clamperl")},
+	"metal-powder":   {statBoost("defense", 2, "ditto")},
+	"soul-dew": {
+		statBoost("special-attack", 1.5, "latias", "latios"),
+		statBoost("special-defense", 1.5, "latias", "latios"),
+	},
+
+	"muscle-band":  {{Kind: ModifierCategory, DamageClass: "physical", Multiplier: 1.1}},
+	"wise-glasses": {{Kind: ModifierCategory, DamageClass: "special", Multiplier: 1.1}},
+	"life-orb":     {{Kind: ModifierBaseDamage, Multiplier: 1.3}},
+	"expert-belt":  {{Kind: ModifierDamage, Multiplier: 1.2, SuperEffectiveOnly: true}},
+
+	"silk-scarf":     {typeBoost("normal")},
+	"charcoal":       {typeBoost("fire")},
+	"mystic-water":   {typeBoost("water")},
+	"miracle-seed":   {typeBoost("grass")},
+	"magnet":         {typeBoost("electric")},
+	"never-melt-ice": {typeBoost("ice")},
+	"black-belt":     {typeBoost("fighting")},
+	"poison-barb":    {typeBoost("poison")},
+	"soft-sand":      {typeBoost("ground")},
+	"sharp-beak":     {typeBoost("flying")},
+	"twisted-spoon":  {typeBoost("psychic")},
+	"silver-powder":  {typeBoost("bug")},
+	"hard-stone":     {typeBoost("rock")},
+	"spell-tag":      {typeBoost("ghost")},
+	"dragon-fang":    {typeBoost("dragon")},
+	"black-glasses":  {typeBoost("dark")},
+	"metal-coat":     {typeBoost("steel")},
+
+	"flame-plate":  {typeBoost("fire")},
+	"splash-plate": {typeBoost("water")},
+	"meadow-plate": {typeBoost("grass")},
+	"zap-plate":    {typeBoost("electric")},
+	"icicle-plate": {typeBoost("ice")},
+	"fist-plate":   {typeBoost("fighting")},
+	"toxic-plate":  {typeBoost("poison")},
+	"earth-plate":  {typeBoost("ground")},
+	"sky-plate":    {typeBoost("flying")},
+	"mind-plate":   {typeBoost("psychic")},
+	"insect-plate": {typeBoost("bug")},
+	"stone-plate":  {typeBoost("rock")},
+	"spooky-plate": {typeBoost("ghost")},
+	"draco-plate":  {typeBoost("dragon")},
+	"dread-plate":  {typeBoost("dark")},
+	"iron-plate":   {typeBoost("steel")},
+	// Sea Incense only gets the usual 1.2 from Generation V
+	"sea-incense":  {{Kind: ModifierType, Type: "water", Multiplier: 1.05}},
+	"wave-incense": {typeBoost("water")},
+	"rose-incense": {typeBoost("grass")},
+	"odd-incense":  {typeBoost("psychic")},
+	"rock-incense": {typeBoost("rock")},
+	"adamant-orb":  {typeBoost("dragon", "dialga"), typeBoost("steel", "dialga")},
+	"lustrous-orb": {typeBoost("dragon", "palkia"), typeBoost("water", "palkia")},
+	"griseous-orb": {typeBoost("dragon", "giratina-origin"), typeBoost("ghost", "giratina-origin")},
+}
+
+// ItemModifiers returns the battle modifiers of a held item, or nil if the
+// item has no direct effect on damage.
+func ItemModifiers(item string) []Modifier {
+	return itemModifiers[item]
+}
+
+// HolderModifiers returns the modifiers of an item that apply to a holder species.
+func HolderModifiers(item, species string) []Modifier {
+	var mods []Modifier
+	for _, m := range itemModifiers[item] {
+		if m.AppliesTo(species) {
+			mods = append(mods, m)
+		}
+	}
+	return mods
+}
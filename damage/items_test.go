@@ -0,0 +1,27 @@
+package damage
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHolderModifiers(t *testing.T) {
+	tests := []struct {
+		item    string
+		species string
+		want    []Modifier
+	}{
+		{"sea-incense", "marill", []Modifier{{Kind: ModifierType, Type: "water", Multiplier: 1.05}}},
+		{"wave-incense", "marill", []Modifier{{Kind: ModifierType, Type: "water", Multiplier: 1.2}}},
+		{"life-orb", "garchomp", []Modifier{{Kind: ModifierBaseDamage, Multiplier: 1.3}}},
+		{"thick-club", "marowak", []Modifier{{Kind: ModifierStat, Stat: "attack", Multiplier: 2, Species: []string{"cubone", "marowak"}}}},
+		// Species-specific items do nothing for other holders
+		{"thick-club", "garchomp", nil},
+		{"leftovers", "garchomp", nil},
+	}
+	for _, tt := range tests {
+		if got := HolderModifiers(tt.item, tt.species); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s on %s: got %+v, want %+v", tt.item, tt.species, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package games
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Game describes a dataset: the PokeAPI version group whose learnsets are used
+// and the regional Pokédex that decides which Pokemon are included.
+type Game struct {
+	Key          string   `json:"key"`
+	Name         string   `json:"name"`
+	VersionGroup string   `json:"version_group"`
+	Versions     []string `json:"versions"`
+	Pokedex      string   `json:"pokedex"`
+	PokedexID    int      `json:"pokedex_id"`
+	Generation   int      `json:"generation"`
+	// FilePrefix names the Firestore collections and data/ files of the game,
+	// e.g. "heartgold" for heartgold-pokemon and heartgold-moves.json.
+	FilePrefix string `json:"-"`
+}
+
+// DefaultKey is the game served when none is requested.
+const DefaultKey = "heartgold-soulsilver"
+
+// All lists every supported game.
+var All = []Game{
+	{
+		Key:          "heartgold-soulsilver",
+		Name:         "HeartGold/SoulSilver",
+		VersionGroup: "heartgold-soulsilver",
+		Versions:     []string{"heartgold", "soulsilver"},
+		Pokedex:      "updated-johto",
+		PokedexID:    7,
+		Generation:   4,
+		FilePrefix:   "heartgold",
+	},
+	{
+		Key:          "platinum",
+		Name:         "Platinum",
+		VersionGroup: "platinum",
+		Versions:     []string{"platinum"},
+		Pokedex:      "extended-sinnoh",
+		PokedexID:    6,
+		Generation:   4,
+		FilePrefix:   "platinum",
+	},
+	{
+		Key:          "diamond-pearl",
+		Name:         "Diamond/Pearl",
+		VersionGroup: "diamond-pearl",
+		Versions:     []string{"diamond", "pearl"},
+		Pokedex:      "original-sinnoh",
+		PokedexID:    5,
+		Generation:   4,
+		FilePrefix:   "diamond-pearl",
+	},
+	{
+		Key:          "firered-leafgreen",
+		Name:         "FireRed/LeafGreen",
+		VersionGroup: "firered-leafgreen",
+		Versions:     []string{"firered", "leafgreen"},
+		Pokedex:      "kanto",
+		PokedexID:    2,
+		Generation:   3,
+		FilePrefix:   "firered-leafgreen",
+	},
+}
+
+// aliases maps short names to game keys.
+var aliases = map[string]string{
+	"hgss":       "heartgold-soulsilver",
+	"heartgold":  "heartgold-soulsilver",
+	"soulsilver": "heartgold-soulsilver",
+	"pt":         "platinum",
+	"dp":         "diamond-pearl",
+	"diamond":    "diamond-pearl",
+	"pearl":      "diamond-pearl",
+	"frlg":       "firered-leafgreen",
+	"firered":    "firered-leafgreen",
+	"leafgreen":  "firered-leafgreen",
+}
+
+// Default returns the default game (HeartGold/SoulSilver).
+func Default() Game {
+	g, _ := Lookup(DefaultKey)
+	return g
+}
+
+// Lookup finds a game by key or alias. An empty key returns the default game.
+func Lookup(key string) (Game, error) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	if key == "" {
+		key = DefaultKey
+	}
+	if alias, ok := aliases[key]; ok {
+		key = alias
+	}
+	for _, g := range All {
+		if g.Key == key {
+			return g, nil
+		}
+	}
+	return Game{}, fmt.Errorf("unknown game: %s", key)
+}
+
+// collection names the Firestore collection of one kind of game data.
+func (g Game) collection(kind string) string {
+	return g.FilePrefix + "-" + kind
+}
+
+// PokemonCollection is the Firestore collection holding the game's Pokemon.
+func (g Game) PokemonCollection() string {
+	return g.collection("pokemon")
+}
+
+// MovesCollection is the Firestore collection holding the game's moves.
+func (g Game) MovesCollection() string {
+	return g.collection("moves")
+}
+
+// ItemsCollection is the Firestore collection holding the game's items.
+func (g Game) ItemsCollection() string {
+	return g.collection("items")
+}
+
+// MachinesCollection is the Firestore collection holding the game's TM/HM
+// to move mappings.
+func (g Game) MachinesCollection() string {
+	return g.collection("machines")
+}
+
+// SpeciesCollection is the Firestore collection holding the species of the
+// game's Pokemon.
+func (g Game) SpeciesCollection() string {
+	return g.collection("species")
+}
+
+// EncountersCollection is the Firestore collection holding where the game's
+// Pokemon are found in the wild.
+func (g Game) EncountersCollection() string {
+	return g.collection("encounters")
+}
+
+// EvolutionsCollection is the Firestore collection holding the evolution
+// chains of the game's Pokemon.
+func (g Game) EvolutionsCollection() string {
+	return g.collection("evolutions")
+}
+
+// AbilitiesCollection is the Firestore collection holding the game's abilities.
+func (g Game) AbilitiesCollection() string {
+	return g.collection("abilities")
+}
+
+// PokemonFile is the data/ file holding the game's Pokemon.
+func (g Game) PokemonFile() string {
+	return g.PokemonCollection() + ".json"
+}
+
+// MovesFile is the data/ file holding the game's moves.
+func (g Game) MovesFile() string {
+	return g.MovesCollection() + ".json"
+}
+
+// AbilitiesFile is the data/ file holding the game's abilities.
+func (g Game) AbilitiesFile() string {
+	return g.AbilitiesCollection() + ".json"
+}
+
+// ItemsFile is the data/ file holding the game's items.
+func (g Game) ItemsFile() string {
+	return g.ItemsCollection() + ".json"
+}
+
+// MachinesFile is the data/ file holding the game's TM/HM to move mappings.
+func (g Game) MachinesFile() string {
+	return g.MachinesCollection() + ".json"
+}
+
+// EvolutionsFile is the data/ file holding the evolution chains of the game's Pokemon.
+func (g Game) EvolutionsFile() string {
+	return g.EvolutionsCollection() + ".json"
+}
+
+// SpeciesFile is the data/ file holding the species of the game's Pokemon.
+func (g Game) SpeciesFile() string {
+	return g.SpeciesCollection() + ".json"
+}
+
+// EncountersFile is the data/ file holding where the game's Pokemon are found
+// in the wild.
+func (g Game) EncountersFile() string {
+	return g.EncountersCollection() + ".json"
+}
@@ -0,0 +1,270 @@
+package games
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// StoryFlag is a story event and the number of badges after which it can
+// happen when gyms are beaten in the usual order.
+type StoryFlag struct {
+	Name        string `json:"name"`
+	Badges      int    `json:"badges"`
+	Description string `json:"description"`
+}
+
+// LevelCap is the highest level of traded or overleveled Pokemon that obey
+// from a number of badges on.
+type LevelCap struct {
+	Badges int `json:"badges"`
+	Level  int `json:"level"`
+}
+
+// Gift is a Pokemon given to the player rather than caught.
+type Gift struct {
+	Pokemon  string `json:"pokemon"`
+	Location string `json:"location"`
+	Badges   int    `json:"badges"`
+}
+
+// areaUnlock is the number of badges needed to reach the location areas
+// whose name starts with prefix.
+type areaUnlock struct {
+	prefix string
+	badges int
+}
+
+// Progression describes when things become available in a playthrough,
+// measured in badges. Badge counts assume gyms are beaten in the usual order;
+// anything not listed becomes available with the default badge counts.
+type Progression struct {
+	MaxBadges int
+	LevelCaps []LevelCap
+	Flags     []StoryFlag
+	Gifts     []Gift
+	// HMs and TMs map machine numbers to the badges needed to get and, for
+	// HMs, use them outside battle.
+	HMs             map[int]int
+	TMs             map[int]int
+	DefaultTMBadges int
+	// Tutors maps tutor locations to the badges needed to reach them.
+	Tutors map[string]int
+	// Methods maps encounter methods to the badges needed to use them, e.g.
+	// surf or good-rod.
+	Methods map[string]int
+	// EggBadges is the badge count at which the Day-Care opens, making egg
+	// moves and breeding available.
+	EggBadges         int
+	areas             []areaUnlock
+	defaultAreaBadges int
+}
+
+// ValidateBadges checks a badge count against the progression.
+func (p *Progression) ValidateBadges(badges int) error {
+	if badges < 0 || badges > p.MaxBadges {
+		return fmt.Errorf("badges must be between 0 and %d, got %d", p.MaxBadges, badges)
+	}
+	return nil
+}
+
+// LevelCap returns the highest level that obeys with the given badges.
+func (p *Progression) LevelCap(badges int) int {
+	level := 0
+	for _, c := range p.LevelCaps {
+		if badges >= c.Badges {
+			level = c.Level
+		}
+	}
+	return level
+}
+
+// FlagsReached returns the story flags that can be set with the given badges.
+func (p *Progression) FlagsReached(badges int) []StoryFlag {
+	var flags []StoryFlag
+	for _, f := range p.Flags {
+		if badges >= f.Badges {
+			flags = append(flags, f)
+		}
+	}
+	return flags
+}
+
+// AreaUnlocked reports whether a location area can be reached with the given
+// badges. The longest matching prefix decides.
+func (p *Progression) AreaUnlocked(area string, badges int) bool {
+	needed, length := p.defaultAreaBadges, -1
+	for _, a := range p.areas {
+		if (area == a.prefix || strings.HasPrefix(area, a.prefix+"-")) && len(a.prefix) > length {
+			needed, length = a.badges, len(a.prefix)
+		}
+	}
+	return badges >= needed
+}
+
+// MethodUnlocked reports whether an encounter method can be used with the
+// given badges. Methods not listed need no badges.
+func (p *Progression) MethodUnlocked(method string, badges int) bool {
+	return badges >= p.Methods[method]
+}
+
+// MachineUnlocked reports whether a TM or HM ("tm" or "hm") can be obtained
+// with the given badges.
+func (p *Progression) MachineUnlocked(kind string, number, badges int) bool {
+	if kind == "hm" {
+		needed, ok := p.HMs[number]
+		return ok && badges >= needed
+	}
+	needed, ok := p.TMs[number]
+	if !ok {
+		needed = p.DefaultTMBadges
+	}
+	return badges >= needed
+}
+
+// TutorUnlocked reports whether the tutors at a location can be reached with
+// the given badges. Unknown locations are only reached with every badge.
+func (p *Progression) TutorUnlocked(location string, badges int) bool {
+	needed, ok := p.Tutors[location]
+	if !ok {
+		needed = p.MaxBadges
+	}
+	return badges >= needed
+}
+
+// GiftsReceived returns the gift Pokemon available with the given badges.
+func (p *Progression) GiftsReceived(badges int) []Gift {
+	var gifts []Gift
+	for _, g := range p.Gifts {
+		if badges >= g.Badges {
+			gifts = append(gifts, g)
+		}
+	}
+	return gifts
+}
+
+// UnlockedHMs returns the numbers of the HMs usable with the given badges, in
+// order.
+func (p *Progression) UnlockedHMs(badges int) []int {
+	var hms []int
+	for number := range p.HMs {
+		if p.MachineUnlocked("hm", number, badges) {
+			hms = append(hms, number)
+		}
+	}
+	sort.Ints(hms)
+	return hms
+}
+
+// progressions holds the progression model of each game by game key.
+var progressions = map[string]*Progression{
+	"heartgold-soulsilver": {
+		MaxBadges: 16,
+		LevelCaps: []LevelCap{
+			{Badges: 0, Level: 10},
+			{Badges: 1, Level: 20},  // Zephyr Badge
+			{Badges: 2, Level: 30},  // Hive Badge
+			{Badges: 4, Level: 50},  // Fog Badge
+			{Badges: 5, Level: 70},  // Storm Badge
+			{Badges: 8, Level: 100}, // Rising Badge
+		},
+		Flags: []StoryFlag{
+			{Name: "starter", Badges: 0, Description: "Starter received from Professor Elm"},
+			{Name: "slowpoke-well", Badges: 1, Description: "Team Rocket driven out of the Slowpoke Well"},
+			{Name: "day-care", Badges: 2, Description: "Route 34 Day-Care reachable"},
+			{Name: "squirt-bottle", Badges: 3, Description: "Sudowoodo on Route 36 can be moved"},
+			{Name: "burned-tower", Badges: 3, Description: "Legendary beasts released in the Burned Tower"},
+			{Name: "lake-of-rage", Badges: 4, Description: "Red Gyarados at the Lake of Rage"},
+			{Name: "radio-tower", Badges: 7, Description: "Team Rocket driven out of the Radio Tower"},
+			{Name: "bell-tower", Badges: 7, Description: "Bell Tower open with the Clear Bell or Tidal Bell"},
+			{Name: "indigo-plateau", Badges: 8, Description: "Victory Road and the Elite Four open"},
+			{Name: "kanto", Badges: 8, Description: "Kanto reachable after becoming Champion"},
+			{Name: "mt-silver", Badges: 16, Description: "Mt. Silver and Red open"},
+		},
+		Gifts: []Gift{
+			{Pokemon: "chikorita", Location: "new-bark-town", Badges: 0},
+			{Pokemon: "cyndaquil", Location: "new-bark-town", Badges: 0},
+			{Pokemon: "totodile", Location: "new-bark-town", Badges: 0},
+			{Pokemon: "togepi", Location: "violet-city", Badges: 1}, // egg from Elm's aide, after the Zephyr Badge
+			{Pokemon: "eevee", Location: "goldenrod-city", Badges: 2},
+			{Pokemon: "shuckle", Location: "cianwood-city", Badges: 4},
+			{Pokemon: "tyrogue", Location: "mt-mortar", Badges: 4},
+			{Pokemon: "dratini", Location: "dragons-den", Badges: 8},
+		},
+		HMs: map[int]int{
+			1: 2,  // Cut: Ilex Forest, Hive Badge
+			2: 5,  // Fly: Cianwood City, Storm Badge
+			3: 4,  // Surf: Ecruteak City, Fog Badge
+			4: 3,  // Strength: Olivine City, Plain Badge
+			5: 7,  // Whirlpool: Team Rocket HQ, Glacier Badge
+			6: 1,  // Rock Smash: Route 36, Zephyr Badge
+			7: 8,  // Waterfall: Ice Path, Rising Badge
+			8: 16, // Rock Climb: Kanto, Earth Badge
+		},
+		TMs: map[int]int{
+			70: 0, // Flash: Sprout Tower
+			51: 1, // Roost: Falkner
+			89: 2, // U-turn: Bugsy
+			16: 2, // Light Screen: Goldenrod Department Store
+			17: 2, // Protect: Goldenrod Department Store
+			20: 2, // Safeguard: Goldenrod Department Store
+			33: 2, // Reflect: Goldenrod Department Store
+			45: 3, // Attract: Whitney
+			30: 4, // Shadow Ball: Morty
+			1:  5, // Focus Punch: Chuck
+			23: 6, // Iron Tail: Jasmine
+			7:  7, // Hail: Pryce
+			59: 8, // Dragon Pulse: Clair
+			// Kanto gyms can be beaten in any order, except Blue's, which
+			// opens after the other seven
+			80: 9,  // Rock Slide: Brock
+			3:  9,  // Water Pulse: Misty
+			34: 9,  // Shock Wave: Lt. Surge
+			19: 9,  // Giga Drain: Erika
+			84: 9,  // Poison Jab: Janine
+			48: 9,  // Skill Swap: Sabrina
+			50: 9,  // Overheat: Blaine
+			92: 15, // Trick Room: Blue
+		},
+		DefaultTMBadges: 8,
+		Tutors: map[string]int{
+			"ilex-forest":     2,
+			"battle-frontier": 4, // Route 40 needs Surf
+			"blackthorn-city": 8,
+		},
+		Methods: map[string]int{
+			"old-rod":       1,
+			"rock-smash":    1,
+			"headbutt":      2,
+			"squirt-bottle": 3,
+			"good-rod":      3,
+			"surf":          4,
+			"super-rod":     8,
+		},
+		EggBadges: 2,
+		areas: []areaUnlock{
+			{"new-bark-town", 0}, {"route-29", 0}, {"cherrygrove-city", 0}, {"route-30", 0},
+			{"route-31", 0}, {"route-46", 0}, {"dark-cave", 0}, {"violet-city", 0},
+			{"sprout-tower", 0}, {"ruins-of-alph", 0}, {"route-36", 0},
+			{"route-32", 1}, {"union-cave", 1}, {"route-33", 1}, {"slowpoke-well", 1}, {"azalea-town", 1},
+			{"ilex-forest", 2}, {"route-34", 2}, {"goldenrod-city", 2}, {"route-35", 2}, {"national-park", 2},
+			{"route-37", 3}, {"ecruteak-city", 3}, {"burned-tower", 3},
+			{"route-38", 3}, {"route-39", 3}, {"olivine-city", 3}, {"olivine-lighthouse", 3},
+			{"route-40", 4}, {"battle-frontier", 4}, {"route-41", 4}, {"cianwood-city", 4}, {"route-42", 4}, {"mt-mortar", 4},
+			{"mahogany-town", 4}, {"route-43", 4}, {"lake-of-rage", 4}, {"route-47", 4}, {"route-48", 4},
+			{"cliff-cave", 4}, {"cliff-edge-gate", 4}, {"safari-zone-gate", 4},
+			{"whirl-islands", 7}, {"route-44", 7}, {"ice-path", 7}, {"blackthorn-city", 7},
+			{"dark-cave-blackthorn-city-entrance", 7}, {"route-45", 7}, {"bell-tower", 7},
+			{"dragons-den", 8}, {"route-27", 8}, {"route-26", 8}, {"tohjo-falls", 8}, {"victory-road", 8},
+			{"mt-silver", 16}, {"cerulean-cave", 16},
+		},
+		// Kanto and anything unlisted
+		defaultAreaBadges: 8,
+	},
+}
+
+// Progression returns the badge progression model of the game, or nil if the
+// game has none.
+func (g Game) Progression() *Progression {
+	return progressions[g.Key]
+}
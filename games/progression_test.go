@@ -0,0 +1,20 @@
+package games
+
+import "testing"
+
+// TestProgressionGatesWithinAreas checks that nothing found in an area is
+// available before the area itself can be reached.
+func TestProgressionGatesWithinAreas(t *testing.T) {
+	for key, p := range progressions {
+		for _, g := range p.Gifts {
+			if !p.AreaUnlocked(g.Location, g.Badges) {
+				t.Errorf("%s: gift %s at %d badges, but %s needs more", key, g.Pokemon, g.Badges, g.Location)
+			}
+		}
+		for location, badges := range p.Tutors {
+			if !p.AreaUnlocked(location, badges) {
+				t.Errorf("%s: tutors at %s at %d badges, but the area needs more", key, location, badges)
+			}
+		}
+	}
+}
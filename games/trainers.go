@@ -0,0 +1,337 @@
+package games
+
+import "strings"
+
+// Trainer classes.
+const (
+	ClassGymLeader      = "gym-leader"
+	ClassEliteFour      = "elite-four"
+	ClassChampion       = "champion"
+	ClassRival          = "rival"
+	ClassPokemonTrainer = "pokemon-trainer"
+)
+
+// TrainerPokemon is a Pokemon of a trainer's team. Types are recorded because
+// some teams use Pokemon outside the regional Pokédex.
+type TrainerPokemon struct {
+	Pokemon string   `json:"pokemon"`
+	Level   int      `json:"level"`
+	Types   []string `json:"types"`
+	Moves   []string `json:"moves"`
+	Item    string   `json:"item,omitempty"`
+}
+
+// Trainer is an important battle of the game, with the team used the first
+// time it is fought.
+type Trainer struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Class    string `json:"class"`
+	Location string `json:"location"`
+	// Badge is the badge won by beating a gym leader.
+	Badge string `json:"badge,omitempty"`
+	// PlayerStarter is the starter the player must have picked for a rival
+	// team to be used.
+	PlayerStarter string           `json:"player_starter,omitempty"`
+	Team          []TrainerPokemon `json:"team"`
+}
+
+// MaxLevel returns the level of the trainer's strongest Pokemon.
+func (t Trainer) MaxLevel() int {
+	level := 0
+	for _, p := range t.Team {
+		level = max(level, p.Level)
+	}
+	return level
+}
+
+// mon builds a trainer's Pokemon. types is space separated.
+func mon(pokemon string, level int, types string, moves ...string) TrainerPokemon {
+	return TrainerPokemon{Pokemon: pokemon, Level: level, Types: strings.Fields(types), Moves: moves}
+}
+
+// holding sets the held item of a trainer's Pokemon.
+func holding(p TrainerPokemon, item string) TrainerPokemon {
+	p.Item = item
+	return p
+}
+
+// rivalStage is one battle against the rival. The rival's starter line and
+// its moves depend on the player's starter; the rest of the team does not.
+type rivalStage struct {
+	location     string
+	team         []TrainerPokemon
+	starterLevel int
+	// starters maps the player's starter to the rival's starter at this stage.
+	starters map[string]TrainerPokemon
+}
+
+// rivalTrainers expands rival stages into one trainer per player starter.
+func rivalTrainers(stages []rivalStage) []Trainer {
+	var trainers []Trainer
+	for _, stage := range stages {
+		for _, playerStarter := range []string{"chikorita", "cyndaquil", "totodile"} {
+			starter := stage.starters[playerStarter]
+			starter.Level = stage.starterLevel
+			team := append(append([]TrainerPokemon{}, stage.team...), starter)
+			trainers = append(trainers, Trainer{
+				ID:            "rival-" + stage.location + "-" + playerStarter,
+				Name:          "Silver",
+				Class:         ClassRival,
+				Location:      stage.location,
+				PlayerStarter: playerStarter,
+				Team:          team,
+			})
+		}
+	}
+	return trainers
+}
+
+// hgssRivalStages lists the battles against Silver, from Cherrygrove City to
+// the post-game one in Mt. Moon. The rival picks the starter with a type
+// advantage over the player's.
+var hgssRivalStages = []rivalStage{
+	{
+		location:     "cherrygrove-city",
+		starterLevel: 5,
+		starters: map[string]TrainerPokemon{
+			"chikorita": mon("cyndaquil", 0, "fire", "tackle", "leer"),
+			"cyndaquil": mon("totodile", 0, "water", "scratch", "leer"),
+			"totodile":  mon("chikorita", 0, "grass", "tackle", "growl"),
+		},
+	},
+	{
+		location: "azalea-town",
+		team: []TrainerPokemon{
+			mon("gastly", 14, "ghost poison", "lick", "spite", "hypnosis", "curse"),
+			mon("zubat", 16, "poison flying", "leech-life", "supersonic", "astonish", "bite"),
+		},
+		starterLevel: 18,
+		starters: map[string]TrainerPokemon{
+			"chikorita": mon("quilava", 0, "fire", "smokescreen", "ember", "quick-attack", "leer"),
+			"cyndaquil": mon("croconaw", 0, "water", "scratch", "leer", "water-gun", "rage"),
+			"totodile":  mon("bayleef", 0, "grass", "tackle", "growl", "razor-leaf", "reflect"),
+		},
+	},
+	{
+		location: "burned-tower",
+		team: []TrainerPokemon{
+			mon("gastly", 20, "ghost poison", "lick", "spite", "mean-look", "curse"),
+			mon("zubat", 20, "poison flying", "leech-life", "supersonic", "astonish", "bite"),
+			mon("magnemite", 18, "electric steel", "tackle", "thunder-shock", "supersonic", "sonic-boom"),
+		},
+		starterLevel: 22,
+		starters: map[string]TrainerPokemon{
+			"chikorita": mon("quilava", 0, "fire", "smokescreen", "ember", "quick-attack", "flame-wheel"),
+			"cyndaquil": mon("croconaw", 0, "water", "water-gun", "rage", "bite", "scary-face"),
+			"totodile":  mon("bayleef", 0, "grass", "razor-leaf", "reflect", "poison-powder", "tackle"),
+		},
+	},
+	{
+		location: "goldenrod-underground",
+		team: []TrainerPokemon{
+			mon("golbat", 30, "poison flying", "leech-life", "supersonic", "bite", "confuse-ray"),
+			mon("magnemite", 28, "electric steel", "thunder-shock", "supersonic", "sonic-boom", "thunder-wave"),
+			mon("haunter", 30, "ghost poison", "lick", "spite", "curse", "night-shade"),
+			mon("sneasel", 32, "dark ice", "leer", "quick-attack", "screech", "faint-attack"),
+		},
+		starterLevel: 32,
+		starters: map[string]TrainerPokemon{
+			"chikorita": mon("typhlosion", 0, "fire", "smokescreen", "flame-wheel", "quick-attack", "swift"),
+			"cyndaquil": mon("feraligatr", 0, "water", "water-gun", "bite", "scary-face", "ice-fang"),
+			"totodile":  mon("meganium", 0, "grass", "razor-leaf", "poison-powder", "body-slam", "light-screen"),
+		},
+	},
+	{
+		location: "victory-road",
+		team: []TrainerPokemon{
+			mon("sneasel", 34, "dark ice", "quick-attack", "screech", "faint-attack", "fury-swipes"),
+			mon("golbat", 35, "poison flying", "leech-life", "bite", "confuse-ray", "wing-attack"),
+			mon("magneton", 34, "electric steel", "thunder-shock", "sonic-boom", "thunder-wave", "supersonic"),
+			mon("haunter", 35, "ghost poison", "lick", "curse", "night-shade", "confuse-ray"),
+			mon("kadabra", 35, "psychic", "disable", "psybeam", "reflect", "recover"),
+		},
+		starterLevel: 38,
+		starters: map[string]TrainerPokemon{
+			"chikorita": mon("typhlosion", 0, "fire", "flame-wheel", "quick-attack", "swift", "lava-plume"),
+			"cyndaquil": mon("feraligatr", 0, "water", "bite", "ice-fang", "scary-face", "waterfall"),
+			"totodile":  mon("meganium", 0, "grass", "razor-leaf", "body-slam", "light-screen", "reflect"),
+		},
+	},
+	{
+		// Post-game, after the Elite Four
+		location: "mt-moon",
+		team: []TrainerPokemon{
+			mon("sneasel", 41, "dark ice", "screech", "faint-attack", "metal-claw", "icy-wind"),
+			mon("crobat", 42, "poison flying", "bite", "confuse-ray", "wing-attack", "cross-poison"),
+			mon("magneton", 41, "electric steel", "thunderbolt", "sonic-boom", "thunder-wave", "tri-attack"),
+			mon("gengar", 41, "ghost poison", "curse", "mean-look", "night-shade", "shadow-ball"),
+			mon("alakazam", 41, "psychic", "disable", "psychic", "reflect", "recover"),
+		},
+		starterLevel: 45,
+		starters: map[string]TrainerPokemon{
+			"chikorita": mon("typhlosion", 0, "fire", "flame-wheel", "quick-attack", "swift", "lava-plume"),
+			"cyndaquil": mon("feraligatr", 0, "water", "bite", "ice-fang", "scary-face", "waterfall"),
+			"totodile":  mon("meganium", 0, "grass", "razor-leaf", "body-slam", "light-screen", "reflect"),
+		},
+	},
+}
+
+// trainers lists the important battles of each game by game key: the gym
+// leaders, the Elite Four and the Champion in story order, then the post-game
+// battles (the Kanto gym leaders in the usual order and Red) and the rival's
+// battles. PokeAPI has no trainer data, so the teams are recorded here.
+var trainers = map[string][]Trainer{
+	"heartgold-soulsilver": append([]Trainer{
+		{ID: "falkner", Name: "Falkner", Class: ClassGymLeader, Location: "violet-city", Badge: "zephyr", Team: []TrainerPokemon{
+			mon("pidgey", 9, "normal flying", "tackle", "mud-slap"),
+			mon("pidgeotto", 13, "normal flying", "tackle", "sand-attack", "gust", "roost"),
+		}},
+		{ID: "bugsy", Name: "Bugsy", Class: ClassGymLeader, Location: "azalea-town", Badge: "hive", Team: []TrainerPokemon{
+			mon("metapod", 15, "bug", "tackle", "harden"),
+			mon("kakuna", 15, "bug poison", "poison-sting", "harden"),
+			mon("scyther", 17, "bug flying", "quick-attack", "leer", "fury-cutter", "u-turn"),
+		}},
+		{ID: "whitney", Name: "Whitney", Class: ClassGymLeader, Location: "goldenrod-city", Badge: "plain", Team: []TrainerPokemon{
+			mon("clefairy", 17, "normal", "double-slap", "mimic", "encore", "metronome"),
+			mon("miltank", 19, "normal", "rollout", "attract", "stomp", "milk-drink"),
+		}},
+		{ID: "morty", Name: "Morty", Class: ClassGymLeader, Location: "ecruteak-city", Badge: "fog", Team: []TrainerPokemon{
+			mon("gastly", 21, "ghost poison", "lick", "spite", "mean-look", "curse"),
+			mon("haunter", 21, "ghost poison", "hypnosis", "mimic", "curse", "night-shade"),
+			mon("haunter", 23, "ghost poison", "spite", "mean-look", "mimic", "night-shade"),
+			mon("gengar", 25, "ghost poison", "hypnosis", "shadow-ball", "mean-look", "dream-eater"),
+		}},
+		{ID: "chuck", Name: "Chuck", Class: ClassGymLeader, Location: "cianwood-city", Badge: "storm", Team: []TrainerPokemon{
+			mon("primeape", 29, "fighting", "leer", "rage", "karate-chop", "fury-swipes"),
+			mon("poliwrath", 31, "water fighting", "hypnosis", "mind-reader", "surf", "dynamic-punch"),
+		}},
+		{ID: "jasmine", Name: "Jasmine", Class: ClassGymLeader, Location: "olivine-city", Badge: "mineral", Team: []TrainerPokemon{
+			mon("magnemite", 30, "electric steel", "thunderbolt", "supersonic", "sonic-boom", "thunder-wave"),
+			mon("magnemite", 30, "electric steel", "thunderbolt", "supersonic", "sonic-boom", "thunder-wave"),
+			mon("steelix", 35, "steel ground", "screech", "sandstorm", "iron-tail", "rock-throw"),
+		}},
+		{ID: "pryce", Name: "Pryce", Class: ClassGymLeader, Location: "mahogany-town", Badge: "glacier", Team: []TrainerPokemon{
+			mon("seel", 30, "water", "headbutt", "icy-wind", "aurora-beam", "rest"),
+			mon("dewgong", 32, "water ice", "headbutt", "icy-wind", "aurora-beam", "rest"),
+			mon("piloswine", 34, "ice ground", "hail", "ice-fang", "blizzard", "earthquake"),
+		}},
+		{ID: "clair", Name: "Clair", Class: ClassGymLeader, Location: "blackthorn-city", Badge: "rising", Team: []TrainerPokemon{
+			mon("gyarados", 38, "water flying", "bite", "dragon-rage", "waterfall", "hyper-beam"),
+			mon("dragonair", 38, "dragon", "thunder-wave", "thunderbolt", "dragon-pulse", "slam"),
+			mon("dragonair", 38, "dragon", "thunder-wave", "ice-beam", "dragon-pulse", "slam"),
+			mon("kingdra", 41, "water dragon", "smokescreen", "surf", "dragon-pulse", "hyper-beam"),
+		}},
+		{ID: "will", Name: "Will", Class: ClassEliteFour, Location: "indigo-plateau", Team: []TrainerPokemon{
+			mon("xatu", 40, "psychic flying", "quick-attack", "future-sight", "confuse-ray", "psychic"),
+			mon("jynx", 41, "ice psychic", "double-slap", "lovely-kiss", "ice-punch", "psychic"),
+			mon("exeggutor", 41, "grass psychic", "reflect", "leech-seed", "egg-bomb", "psychic"),
+			mon("slowbro", 41, "water psychic", "curse", "amnesia", "body-slam", "psychic"),
+			mon("xatu", 42, "psychic flying", "quick-attack", "future-sight", "confuse-ray", "psychic"),
+		}},
+		{ID: "koga", Name: "Koga", Class: ClassEliteFour, Location: "indigo-plateau", Team: []TrainerPokemon{
+			mon("ariados", 40, "bug poison", "double-team", "spider-web", "baton-pass", "poison-jab"),
+			mon("venomoth", 41, "bug poison", "supersonic", "gust", "psychic", "toxic"),
+			mon("forretress", 43, "bug steel", "protect", "swift", "explosion", "spikes"),
+			mon("muk", 42, "poison", "minimize", "acid-armor", "sludge-bomb", "toxic"),
+			mon("crobat", 44, "poison flying", "double-team", "quick-attack", "wing-attack", "toxic"),
+		}},
+		{ID: "bruno", Name: "Bruno", Class: ClassEliteFour, Location: "indigo-plateau", Team: []TrainerPokemon{
+			mon("hitmontop", 42, "fighting", "pursuit", "quick-attack", "dig", "detect"),
+			mon("hitmonlee", 42, "fighting", "swagger", "double-kick", "hi-jump-kick", "foresight"),
+			mon("hitmonchan", 42, "fighting", "thunder-punch", "ice-punch", "fire-punch", "mach-punch"),
+			mon("onix", 43, "rock ground", "bind", "earthquake", "sandstorm", "rock-slide"),
+			mon("machamp", 46, "fighting", "rock-slide", "foresight", "vital-throw", "cross-chop"),
+		}},
+		{ID: "karen", Name: "Karen", Class: ClassEliteFour, Location: "indigo-plateau", Team: []TrainerPokemon{
+			mon("umbreon", 42, "dark", "double-team", "confuse-ray", "faint-attack", "payback"),
+			mon("vileplume", 42, "grass poison", "stun-spore", "acid", "moonlight", "petal-dance"),
+			mon("gengar", 45, "ghost poison", "lick", "spite", "curse", "destiny-bond"),
+			mon("murkrow", 44, "dark flying", "quick-attack", "whirlwind", "pursuit", "faint-attack"),
+			mon("houndoom", 47, "dark fire", "roar", "pursuit", "flamethrower", "crunch"),
+		}},
+		{ID: "lance", Name: "Lance", Class: ClassChampion, Location: "indigo-plateau", Team: []TrainerPokemon{
+			mon("gyarados", 46, "water flying", "flail", "rain-dance", "surf", "hyper-beam"),
+			mon("dragonite", 47, "dragon flying", "thunder-wave", "twister", "thunder", "hyper-beam"),
+			mon("dragonite", 47, "dragon flying", "thunder-wave", "twister", "blizzard", "hyper-beam"),
+			mon("aerodactyl", 46, "rock flying", "wing-attack", "ancient-power", "rock-slide", "hyper-beam"),
+			mon("charizard", 46, "fire flying", "flamethrower", "wing-attack", "slash", "hyper-beam"),
+			mon("dragonite", 50, "dragon flying", "fire-blast", "safeguard", "outrage", "hyper-beam"),
+		}},
+		{ID: "lt-surge", Name: "Lt. Surge", Class: ClassGymLeader, Location: "vermilion-city", Badge: "thunder", Team: []TrainerPokemon{
+			mon("raichu", 51, "electric", "thunder-wave", "quick-attack", "thunderbolt", "thunder"),
+			mon("electrode", 47, "electric", "screech", "double-team", "swift", "explosion"),
+			mon("magneton", 47, "electric steel", "lock-on", "double-team", "swift", "zap-cannon"),
+			mon("electrode", 47, "electric", "screech", "double-team", "swift", "explosion"),
+			mon("electabuzz", 53, "electric", "quick-attack", "thunder-punch", "light-screen", "thunderbolt"),
+		}},
+		{ID: "sabrina", Name: "Sabrina", Class: ClassGymLeader, Location: "saffron-city", Badge: "marsh", Team: []TrainerPokemon{
+			mon("espeon", 53, "psychic", "sand-attack", "quick-attack", "swift", "psychic"),
+			mon("mr-mime", 53, "psychic", "barrier", "reflect", "baton-pass", "psychic"),
+			mon("alakazam", 55, "psychic", "recover", "future-sight", "psychic", "reflect"),
+		}},
+		{ID: "erika", Name: "Erika", Class: ClassGymLeader, Location: "celadon-city", Badge: "rainbow", Team: []TrainerPokemon{
+			mon("tangela", 52, "grass", "vine-whip", "bind", "giga-drain", "sleep-powder"),
+			mon("jumpluff", 51, "grass flying", "mega-drain", "leech-seed", "cotton-spore", "u-turn"),
+			mon("victreebel", 56, "grass poison", "sunny-day", "synthesis", "acid", "razor-leaf"),
+			mon("bellossom", 56, "grass", "sunny-day", "synthesis", "petal-dance", "solar-beam"),
+		}},
+		{ID: "janine", Name: "Janine", Class: ClassGymLeader, Location: "fuchsia-city", Badge: "soul", Team: []TrainerPokemon{
+			mon("crobat", 47, "poison flying", "screech", "supersonic", "confuse-ray", "wing-attack"),
+			mon("weezing", 44, "poison", "smog", "sludge-bomb", "toxic", "explosion"),
+			mon("ariados", 47, "bug poison", "scary-face", "toxic", "double-team", "poison-jab"),
+			mon("ariados", 47, "bug poison", "scary-face", "toxic", "double-team", "poison-jab"),
+			mon("venomoth", 50, "bug poison", "supersonic", "foresight", "psybeam", "toxic"),
+		}},
+		{ID: "misty", Name: "Misty", Class: ClassGymLeader, Location: "cerulean-city", Badge: "cascade", Team: []TrainerPokemon{
+			mon("golduck", 49, "water", "surf", "disable", "psych-up", "psychic"),
+			mon("quagsire", 49, "water ground", "surf", "amnesia", "earthquake", "rain-dance"),
+			mon("lapras", 52, "water ice", "surf", "perish-song", "blizzard", "rain-dance"),
+			mon("starmie", 54, "water psychic", "surf", "confuse-ray", "recover", "ice-beam"),
+		}},
+		{ID: "brock", Name: "Brock", Class: ClassGymLeader, Location: "pewter-city", Badge: "boulder", Team: []TrainerPokemon{
+			mon("graveler", 51, "rock ground", "defense-curl", "rock-slide", "rollout", "earthquake"),
+			mon("rhyhorn", 51, "ground rock", "fury-attack", "scary-face", "earthquake", "horn-drill"),
+			mon("omastar", 53, "rock water", "bite", "surf", "protect", "spike-cannon"),
+			mon("onix", 54, "rock ground", "bind", "rock-slide", "bide", "sandstorm"),
+			mon("kabutops", 52, "rock water", "slash", "surf", "endure", "giga-drain"),
+		}},
+		{ID: "blaine", Name: "Blaine", Class: ClassGymLeader, Location: "seafoam-islands", Badge: "volcano", Team: []TrainerPokemon{
+			mon("magcargo", 54, "fire rock", "curse", "smog", "flamethrower", "rock-slide"),
+			mon("magmar", 54, "fire", "thunder-punch", "fire-punch", "sunny-day", "confuse-ray"),
+			mon("rapidash", 59, "fire", "quick-attack", "fire-spin", "fury-attack", "fire-blast"),
+		}},
+		{ID: "blue", Name: "Blue", Class: ClassGymLeader, Location: "viridian-city", Badge: "earth", Team: []TrainerPokemon{
+			mon("pidgeot", 60, "normal flying", "quick-attack", "whirlwind", "wing-attack", "mirror-move"),
+			mon("alakazam", 54, "psychic", "disable", "recover", "psychic", "reflect"),
+			mon("rhydon", 56, "ground rock", "fury-attack", "sandstorm", "rock-slide", "earthquake"),
+			mon("gyarados", 58, "water flying", "twister", "hydro-pump", "rain-dance", "hyper-beam"),
+			mon("exeggutor", 58, "grass psychic", "sunny-day", "leech-seed", "egg-bomb", "solar-beam"),
+			mon("arcanine", 58, "fire", "roar", "swift", "flame-wheel", "extreme-speed"),
+		}},
+		{ID: "red", Name: "Red", Class: ClassPokemonTrainer, Location: "mt-silver", Team: []TrainerPokemon{
+			holding(mon("pikachu", 88, "electric", "volt-tackle", "iron-tail", "quick-attack", "thunderbolt"), "light-ball"),
+			mon("espeon", 84, "psychic", "psychic", "shadow-ball", "reflect", "morning-sun"),
+			mon("snorlax", 82, "normal", "crunch", "shadow-ball", "blizzard", "rest"),
+			mon("venusaur", 84, "grass poison", "frenzy-plant", "giga-drain", "sunny-day", "synthesis"),
+			mon("charizard", 84, "fire flying", "blast-burn", "flare-blitz", "air-slash", "dragon-pulse"),
+			mon("blastoise", 84, "water", "hydro-cannon", "blizzard", "flash-cannon", "focus-blast"),
+		}},
+	}, rivalTrainers(hgssRivalStages)...),
+}
+
+// Trainers returns the important trainers of the game, in story order, or nil
+// if none are recorded.
+func (g Game) Trainers() []Trainer {
+	return trainers[g.Key]
+}
+
+// Trainer returns the trainer of the game with the given ID.
+func (g Game) Trainer(id string) (Trainer, bool) {
+	for _, t := range trainers[g.Key] {
+		if t.ID == id {
+			return t, true
+		}
+	}
+	return Trainer{}, false
+}
@@ -0,0 +1,33 @@
+package games
+
+import "testing"
+
+func TestRivalTrainers(t *testing.T) {
+	game, err := Lookup("heartgold-soulsilver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	counter := map[string]string{"chikorita": "typhlosion", "cyndaquil": "feraligatr", "totodile": "meganium"}
+	for _, id := range []string{"rival-mt-moon-chikorita", "rival-mt-moon-cyndaquil", "rival-mt-moon-totodile"} {
+		trainer, ok := game.Trainer(id)
+		if !ok {
+			t.Errorf("%s: not found", id)
+			continue
+		}
+		starter := trainer.Team[len(trainer.Team)-1]
+		if want := counter[trainer.PlayerStarter]; starter.Pokemon != want || starter.Level != 45 {
+			t.Errorf("%s: got starter %s at %d, want %s at 45", id, starter.Pokemon, starter.Level, want)
+		}
+		if trainer.MaxLevel() != 45 || len(trainer.Team) != 6 {
+			t.Errorf("%s: got %d Pokemon up to level %d, want 6 up to 45", id, len(trainer.Team), trainer.MaxLevel())
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, trainer := range game.Trainers() {
+		if seen[trainer.ID] {
+			t.Errorf("duplicate trainer ID %s", trainer.ID)
+		}
+		seen[trainer.ID] = true
+	}
+}
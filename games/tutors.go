@@ -0,0 +1,87 @@
+package games
+
+// Tutor is where and for how much a move tutor teaches a move.
+type Tutor struct {
+	Move     string `json:"move"`
+	Location string `json:"location"`
+	// Cost is the price of each lesson in Currency; 0 means free.
+	Cost     int    `json:"cost"`
+	Currency string `json:"currency,omitempty"`
+	Notes    string `json:"notes,omitempty"`
+}
+
+// Currencies used by move tutors.
+const (
+	CurrencyBP = "bp" // Battle Points
+)
+
+// frontierTutor is a Battle Frontier tutor lesson paid in BP.
+func frontierTutor(move string, cost int) Tutor {
+	return Tutor{Move: move, Location: "battle-frontier", Cost: cost, Currency: CurrencyBP}
+}
+
+// tutors lists the move tutors of each game by game key. PokeAPI only records
+// that a move is learned from a tutor, not where or at what price.
+var tutors = map[string][]Tutor{
+	"heartgold-soulsilver": {
+		{Move: "headbutt", Location: "ilex-forest"},
+		{Move: "draco-meteor", Location: "blackthorn-city", Notes: "Dragon types with maximum friendship"},
+		{Move: "frenzy-plant", Location: "blackthorn-city", Notes: "Fully evolved Grass starters with maximum friendship"},
+		{Move: "blast-burn", Location: "blackthorn-city", Notes: "Fully evolved Fire starters with maximum friendship"},
+		{Move: "hydro-cannon", Location: "blackthorn-city", Notes: "Fully evolved Water starters with maximum friendship"},
+
+		frontierTutor("air-cutter", 40),
+		frontierTutor("fury-cutter", 40),
+		frontierTutor("icy-wind", 40),
+		frontierTutor("knock-off", 40),
+		frontierTutor("mud-slap", 40),
+		frontierTutor("ominous-wind", 40),
+		frontierTutor("snore", 40),
+		frontierTutor("spite", 40),
+		frontierTutor("swift", 40),
+		frontierTutor("uproar", 40),
+
+		frontierTutor("aqua-tail", 48),
+		frontierTutor("bounce", 48),
+		frontierTutor("fire-punch", 48),
+		frontierTutor("ice-punch", 48),
+		frontierTutor("thunder-punch", 48),
+		frontierTutor("iron-head", 48),
+		frontierTutor("seed-bomb", 48),
+		frontierTutor("signal-beam", 48),
+		frontierTutor("superpower", 48),
+		frontierTutor("trick", 48),
+		frontierTutor("twister", 48),
+		frontierTutor("vacuum-wave", 48),
+		frontierTutor("zen-headbutt", 48),
+
+		frontierTutor("earth-power", 64),
+		frontierTutor("endeavor", 64),
+		frontierTutor("gastro-acid", 64),
+		frontierTutor("gunk-shot", 64),
+		frontierTutor("heat-wave", 64),
+		frontierTutor("helping-hand", 64),
+		frontierTutor("iron-defense", 64),
+		frontierTutor("last-resort", 64),
+		frontierTutor("magnet-rise", 64),
+		frontierTutor("outrage", 64),
+		frontierTutor("sucker-punch", 64),
+		frontierTutor("synthesis", 64),
+	},
+}
+
+// Tutors returns the move tutors of the game, or nil if none are recorded.
+func (g Game) Tutors() []Tutor {
+	return tutors[g.Key]
+}
+
+// TutorsForMove returns the tutors that teach a move in the game.
+func (g Game) TutorsForMove(move string) []Tutor {
+	var found []Tutor
+	for _, t := range tutors[g.Key] {
+		if t.Move == move {
+			found = append(found, t)
+		}
+	}
+	return found
+}
@@ -6,10 +6,14 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"pokeproject/api"
+	"pokeproject/games"
 	"pokeproject/scripts"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"cloud.google.com/go/firestore"
@@ -86,34 +90,72 @@ func getClientIP(r *http.Request) string {
 }
 
 func main() {
+	gameFlag := flag.String("game", games.DefaultKey, "Game dataset for -pokemon, -moves, -export and -ingest")
 	pokemonFlag := flag.Bool("pokemon", false, "Populate Pokémon (requires Firestore)")
+	dryRunFlag := flag.Bool("dry-run", false, "With -pokemon, print the changes without writing to Firestore")
 	movesFlag := flag.Bool("moves", false, "Populate moves (requires Firestore)")
 	exportFlag := flag.Bool("export", false, "Export Firestore data to JSON")
+	ingestFlag := flag.Bool("ingest", false, "Build data/ JSON straight from PokeAPI (no Firestore)")
+	mirrorFlag := flag.String("mirror", "", "Directory of saved PokeAPI responses to use with -ingest")
+	outFlag := flag.String("out", "data", "Output directory for -ingest")
+	cacheFlag := flag.String("cache", filepath.Join(".cache", "pokeapi"), "Directory where fetched PokeAPI responses are cached (empty disables)")
+	watchFlag := flag.Duration("watch", 0, "Poll data/ for changes and reload at this interval (0 disables)")
+	runsFlag := flag.String("runs", "runs.json", "File where Nuzlocke runs are saved")
 	flag.Parse()
 
+	game, err := games.Lookup(*gameFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	if *pokemonFlag {
-		scripts.PopulateHeartgold()
+		scripts.PopulatePokemon(game, *cacheFlag, *dryRunFlag)
 		return
 	}
 	if *movesFlag {
-		scripts.PopulateMoves()
+		scripts.PopulateMoves(game, *cacheFlag)
 		return
 	}
 	if *exportFlag {
-		scripts.ExportJSON()
+		scripts.ExportJSON(game)
+		return
+	}
+	if *ingestFlag {
+		scripts.Ingest(game, *mirrorFlag, *outFlag, *cacheFlag)
 		return
 	}
 
-	startServer()
+	startServer(*watchFlag, *runsFlag)
 }
 
-func startServer() {
+func startServer(watchInterval time.Duration, runsPath string) {
 	log.Println("Loading data...")
-	cache, err := api.NewCacheFromJSON("data")
+	store, err := api.NewStore("data")
 	if err != nil {
 		log.Fatalf("Could not load data from JSON files: %v\nRun 'go run main.go -export' first to generate the data files.", err)
 	}
-	log.Printf("Cache ready: %d Pokemon, %d moves", len(cache.PokemonRaw), len(cache.MovesRaw))
+	for _, cache := range store.Caches() {
+		log.Printf("Cache ready for %s: %d Pokemon, %d moves", cache.Game.Key, len(cache.Pokemon), len(cache.Moves))
+	}
+	runs, err := api.OpenRunStore(runsPath)
+	if err != nil {
+		log.Fatalf("Could not load Nuzlocke runs: %v", err)
+	}
+	log.Printf("Loaded %d Nuzlocke runs from %s", runs.Len(), runsPath)
+
+	// Reload data on SIGHUP, and on file changes if -watch is set
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			log.Println("SIGHUP received, reloading data...")
+			store.Reload()
+		}
+	}()
+	if watchInterval > 0 {
+		log.Printf("Watching data/ for changes every %s", watchInterval)
+		go store.Watch(watchInterval, nil)
+	}
 
 	rl := newRateLimiter()
 	mux := http.NewServeMux()
@@ -124,6 +166,10 @@ func startServer() {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 			return
 		}
+		cache, ok := store.ForRequest(w, r)
+		if !ok {
+			return
+		}
 		api.GetPokemonListCached(w, r, cache)
 	})
 
@@ -132,8 +178,20 @@ func startServer() {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 			return
 		}
+		cache, ok := store.ForRequest(w, r)
+		if !ok {
+			return
+		}
 		if strings.HasSuffix(r.URL.Path, "/moves") {
 			api.GetPokemonMovesCached(w, r, cache)
+		} else if strings.HasSuffix(r.URL.Path, "/stats") {
+			api.GetPokemonStatsCached(w, r, cache)
+		} else if strings.HasSuffix(r.URL.Path, "/locations") {
+			api.GetPokemonLocationsCached(w, r, cache)
+		} else if strings.HasSuffix(r.URL.Path, "/breeding") {
+			api.GetPokemonBreedingCached(w, r, cache)
+		} else if strings.HasSuffix(r.URL.Path, "/evolutions") {
+			api.GetPokemonEvolutionsCached(w, r, cache)
 		} else {
 			api.GetPokemonByNameCached(w, r, cache)
 		}
@@ -147,15 +205,213 @@ func startServer() {
 		api.GetTypeEffectiveness(w, r)
 	})
 
+	mux.HandleFunc("/api/natures", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		api.GetNatures(w, r)
+	})
+
 	mux.HandleFunc("/api/moves/", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 			return
 		}
+		cache, ok := store.ForRequest(w, r)
+		if !ok {
+			return
+		}
 		api.GetMoveByNameCached(w, r, cache)
 	})
 
+	mux.HandleFunc("/api/abilities/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		cache, ok := store.ForRequest(w, r)
+		if !ok {
+			return
+		}
+		api.GetAbilityByNameCached(w, r, cache)
+	})
+
+	mux.HandleFunc("/api/items", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		cache, ok := store.ForRequest(w, r)
+		if !ok {
+			return
+		}
+		api.SearchItemsCached(w, r, cache)
+	})
+
+	mux.HandleFunc("/api/items/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		cache, ok := store.ForRequest(w, r)
+		if !ok {
+			return
+		}
+		api.GetItemByNameCached(w, r, cache)
+	})
+
+	mux.HandleFunc("/api/locations/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		cache, ok := store.ForRequest(w, r)
+		if !ok {
+			return
+		}
+		api.GetLocationAreaCached(w, r, cache)
+	})
+
+	mux.HandleFunc("/api/machines", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		cache, ok := store.ForRequest(w, r)
+		if !ok {
+			return
+		}
+		api.GetMachinesCached(w, r, cache)
+	})
+
+	mux.HandleFunc("/api/trainers", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		cache, ok := store.ForRequest(w, r)
+		if !ok {
+			return
+		}
+		api.GetTrainersCached(w, r, cache)
+	})
+
+	mux.HandleFunc("/api/trainers/", func(w http.ResponseWriter, r *http.Request) {
+		matchup := strings.HasSuffix(r.URL.Path, "/matchup")
+		if (matchup && r.Method != http.MethodPost) || (!matchup && r.Method != http.MethodGet) {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		cache, ok := store.ForRequest(w, r)
+		if !ok {
+			return
+		}
+		if matchup {
+			run, ok := runs.ForRequest(w, r)
+			if !ok {
+				return
+			}
+			api.TrainerMatchupCached(w, r, cache, run)
+		} else {
+			api.GetTrainerCached(w, r, cache)
+		}
+	})
+
+	mux.HandleFunc("/api/progression", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		cache, ok := store.ForRequest(w, r)
+		if !ok {
+			return
+		}
+		api.GetProgressionCached(w, r, cache)
+	})
+
+	mux.HandleFunc("/api/team/analyze", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		cache, ok := store.ForRequest(w, r)
+		if !ok {
+			return
+		}
+		run, ok := runs.ForRequest(w, r)
+		if !ok {
+			return
+		}
+		api.AnalyzeTeamCached(w, r, cache, run)
+	})
+
+	mux.HandleFunc("/api/runs", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			api.GetRuns(w, r, runs)
+		case http.MethodPost:
+			cache, ok := store.ForRequest(w, r)
+			if !ok {
+				return
+			}
+			api.CreateRunCached(w, r, runs, cache)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	mux.HandleFunc("/api/runs/", func(w http.ResponseWriter, r *http.Request) {
+		path := strings.Trim(r.URL.Path, "/")
+		switch {
+		case strings.HasSuffix(path, "/party") && r.Method == http.MethodPut:
+			api.SetRunParty(w, r, runs, store)
+		case strings.HasSuffix(path, "/encounters") && r.Method == http.MethodPost:
+			api.AddRunEncounter(w, r, runs, store)
+		case strings.Contains(path, "/encounters/") && r.Method == http.MethodPatch:
+			api.UpdateRunEncounter(w, r, runs, store)
+		case strings.Count(path, "/") == 2 && r.Method == http.MethodGet:
+			api.GetRun(w, r, runs)
+		case strings.Count(path, "/") == 2 && r.Method == http.MethodDelete:
+			api.DeleteRun(w, r, runs)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	mux.HandleFunc("/api/damage", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		cache, ok := store.ForRequest(w, r)
+		if !ok {
+			return
+		}
+		api.CalculateDamageCached(w, r, cache)
+	})
+
+	mux.HandleFunc("/api/games", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		api.GetGames(w, r, store)
+	})
+
+	mux.HandleFunc("/api/admin/reload", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		api.ReloadData(w, r, store)
+	})
+
 	mux.HandleFunc("/api/search", func(w http.ResponseWriter, r *http.Request) {
+		cache, ok := store.ForRequest(w, r)
+		if !ok {
+			return
+		}
 		api.SearchCached(w, r, cache)
 	})
 
@@ -180,7 +436,7 @@ func startServer() {
 	// Wrap with rate limiter + CORS (CORS still useful for local dev)
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
 		if r.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusOK)
@@ -0,0 +1,219 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PokeAPIBaseURL is the root of the PokeAPI REST API.
+const PokeAPIBaseURL = "https://pokeapi.co/api/v2/"
+
+// DefaultWorkers is the number of concurrent requests used by the populate scripts.
+const DefaultWorkers = 8
+
+// ResourcePath converts a full PokeAPI URL into a resource path such as "move/89".
+func ResourcePath(url string) string {
+	return strings.Trim(strings.TrimPrefix(url, PokeAPIBaseURL), "/")
+}
+
+// Fetcher downloads PokeAPI resources with a bounded worker pool, a shared rate
+// limit, exponential backoff on 429/5xx responses and an on-disk response cache.
+// Cached responses are never fetched again, so an interrupted run resumes where
+// it stopped, unless Refresh is set. A Fetcher is safe for concurrent use.
+type Fetcher struct {
+	Client     *http.Client
+	Workers    int           // concurrent requests in FetchAll
+	Interval   time.Duration // minimum time between two requests, across all workers
+	MaxRetries int           // retries after the first attempt
+	BaseDelay  time.Duration // first backoff delay, doubled on every retry
+	MaxDelay   time.Duration // backoff cap
+	CacheDir   string        // where responses are saved; "" disables the cache
+	// Refresh fetches every response again instead of reading it from the
+	// cache, and saves the new one there.
+	Refresh bool
+
+	mu   sync.Mutex
+	next time.Time // earliest time the next request may start
+}
+
+// NewFetcher returns a Fetcher with sensible defaults for PokeAPI: 8 workers,
+// at most 10 requests per second and up to 5 retries.
+func NewFetcher(cacheDir string) *Fetcher {
+	return &Fetcher{
+		Client:     &http.Client{Timeout: 30 * time.Second},
+		Workers:    DefaultWorkers,
+		Interval:   100 * time.Millisecond,
+		MaxRetries: 5,
+		BaseDelay:  1 * time.Second,
+		MaxDelay:   30 * time.Second,
+		CacheDir:   cacheDir,
+	}
+}
+
+// Get returns the body of a PokeAPI URL, from the cache when possible.
+func (f *Fetcher) Get(url string) ([]byte, error) {
+	cachePath := f.cachePath(url)
+	if cachePath != "" && !f.Refresh {
+		if body, err := os.ReadFile(cachePath); err == nil {
+			return body, nil
+		}
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= f.MaxRetries; attempt++ {
+		if attempt > 0 {
+			delay := f.backoff(attempt, lastErr)
+			log.Printf("Retrying %s in %s (attempt %d/%d): %v", url, delay, attempt, f.MaxRetries, lastErr)
+			time.Sleep(delay)
+		}
+
+		body, err := f.fetch(url)
+		if err == nil {
+			if cachePath != "" {
+				if err := writeCacheFile(cachePath, body); err != nil {
+					log.Printf("Warning: could not cache %s: %v", url, err)
+				}
+			}
+			return body, nil
+		}
+		lastErr = err
+		if statusErr, ok := err.(*StatusError); ok && !statusErr.Retryable() {
+			break
+		}
+	}
+	return nil, fmt.Errorf("error fetching %s: %w", url, lastErr)
+}
+
+// FetchAll fetches every URL with the worker pool and calls handle with each
+// result. handle is called concurrently from several workers.
+func (f *Fetcher) FetchAll(urls []string, handle func(i int, body []byte, err error)) {
+	Parallel(len(urls), f.Workers, func(i int) {
+		body, err := f.Get(urls[i])
+		handle(i, body, err)
+	})
+}
+
+// StatusError is returned for non-200 responses.
+type StatusError struct {
+	StatusCode int
+	RetryAfter time.Duration // from the Retry-After header, if any
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("status %d", e.StatusCode)
+}
+
+// Retryable reports whether the request may succeed if repeated.
+func (e *StatusError) Retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// fetch performs a single rate-limited request.
+func (f *Fetcher) fetch(url string) ([]byte, error) {
+	f.wait()
+
+	resp, err := f.Client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		statusErr := &StatusError{StatusCode: resp.StatusCode}
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			statusErr.RetryAfter = time.Duration(secs) * time.Second
+		}
+		return nil, statusErr
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// wait blocks until the rate limit allows another request.
+func (f *Fetcher) wait() {
+	f.mu.Lock()
+	now := time.Now()
+	start := now
+	if f.next.After(now) {
+		start = f.next
+	}
+	f.next = start.Add(f.Interval)
+	f.mu.Unlock()
+
+	time.Sleep(start.Sub(now))
+}
+
+// backoff returns the delay before a retry, honoring Retry-After when present.
+func (f *Fetcher) backoff(attempt int, lastErr error) time.Duration {
+	delay := f.BaseDelay << (attempt - 1)
+	if statusErr, ok := lastErr.(*StatusError); ok && statusErr.RetryAfter > delay {
+		delay = statusErr.RetryAfter
+	}
+	if delay > f.MaxDelay {
+		delay = f.MaxDelay
+	}
+	return delay
+}
+
+// MirrorPath returns where a resource path such as "move/89" is stored in a
+// saved copy of PokeAPI: <dir>/move/89/index.json, the layout of the
+// data/api/v2 directory of the PokeAPI api-data repository. Query strings are
+// dropped, so "item?limit=100000" is item/index.json: list requests always ask
+// for every resource, which is what api-data's index files hold.
+func MirrorPath(dir, path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	return filepath.Join(dir, filepath.FromSlash(strings.Trim(path, "/")), "index.json")
+}
+
+// cachePath maps a URL to its MirrorPath in CacheDir, so the cache can be
+// used as an ingest -mirror directory.
+func (f *Fetcher) cachePath(url string) string {
+	if f.CacheDir == "" {
+		return ""
+	}
+	return MirrorPath(f.CacheDir, ResourcePath(url))
+}
+
+// writeCacheFile writes a cached response through a temporary file, so an
+// interrupted run never leaves a truncated entry behind.
+func writeCacheFile(path string, body []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, body, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Parallel calls fn for every index in [0, n) using at most workers goroutines
+// and returns when all calls are done.
+func Parallel(n, workers int, fn func(i int)) {
+	if workers < 1 {
+		workers = 1
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
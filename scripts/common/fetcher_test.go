@@ -0,0 +1,43 @@
+package common
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetcherRefresh(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprintf(w, "response %d", requests)
+	}))
+	defer server.Close()
+
+	f := NewFetcher(t.TempDir())
+	f.Interval = 0
+	url := server.URL + "/pokemon/194/"
+
+	tests := []struct {
+		refresh bool
+		want    string
+	}{
+		{false, "response 1"},
+		// Read from the cache
+		{false, "response 1"},
+		{true, "response 2"},
+		// The refreshed response replaced the cached one
+		{false, "response 2"},
+	}
+	for i, tt := range tests {
+		f.Refresh = tt.refresh
+		body, err := f.Get(url)
+		if err != nil {
+			t.Fatalf("get %d: %v", i, err)
+		}
+		if string(body) != tt.want {
+			t.Errorf("get %d, refresh=%v: got %q, want %q", i, tt.refresh, body, tt.want)
+		}
+	}
+}
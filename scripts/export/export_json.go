@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"pokeproject/games"
 	"pokeproject/scripts/common"
 
 	"cloud.google.com/go/firestore"
@@ -15,9 +16,9 @@ import (
 	"google.golang.org/api/option"
 )
 
-// ExportJSON exports Firestore collections to JSON files in data/ directory.
+// ExportJSON exports a game's Firestore collections to JSON files in data/ directory.
 // This allows the backend to run without Firestore in production.
-func ExportJSON() {
+func ExportJSON(game games.Game) {
 	projectRoot := common.GetProjectRoot()
 
 	if err := godotenv.Load(filepath.Join(projectRoot, ".env")); err != nil {
@@ -43,10 +44,10 @@ func ExportJSON() {
 	}
 
 	// Export Pokemon
-	exportCollection(ctx, client, "heartgold-pokemon", filepath.Join(dataDir, "heartgold-pokemon.json"))
+	exportCollection(ctx, client, game.PokemonCollection(), filepath.Join(dataDir, game.PokemonFile()))
 
 	// Export Moves
-	exportCollection(ctx, client, "heartgold-moves", filepath.Join(dataDir, "heartgold-moves.json"))
+	exportCollection(ctx, client, game.MovesCollection(), filepath.Join(dataDir, game.MovesFile()))
 
 	fmt.Println("Export complete!")
 }
@@ -0,0 +1,466 @@
+package ingest
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"pokeproject/games"
+	"pokeproject/scripts/common"
+	"pokeproject/scripts/moves"
+	"pokeproject/scripts/pokemon"
+	"pokeproject/typeeffectiveness"
+)
+
+// dataFile is a data/ file produced by an ingest run.
+type dataFile struct {
+	name  string
+	data  interface{}
+	count int
+	what  string
+}
+
+// Run builds a game's data/ JSON files straight from PokeAPI responses, without
+// Firestore. The files have the same normalized shape as the ones produced by
+// -export. Everything is fetched before anything is written, so a fetch or
+// decode error leaves the existing files untouched. The files are then written
+// to a temporary directory and renamed into place one after the other.
+func Run(game games.Game, source Source, outDir string) error {
+	pokemonList, speciesList, err := fetchPokemon(game, source)
+	if err != nil {
+		return err
+	}
+	moveList, err := fetchMoves(game, source)
+	if err != nil {
+		return err
+	}
+	abilityList, err := fetchAbilities(game, source, pokemonList)
+	if err != nil {
+		return err
+	}
+	chainList, err := fetchEvolutions(source, speciesList)
+	if err != nil {
+		return err
+	}
+	encounterList, err := fetchEncounters(game, source, pokemonList)
+	if err != nil {
+		return err
+	}
+	itemList, machineList, err := fetchItems(game, source)
+	if err != nil {
+		return err
+	}
+
+	return writeDataFiles(outDir, []dataFile{
+		{game.PokemonFile(), pokemonList, len(pokemonList), "Pokémon"},
+		{game.MovesFile(), moveList, len(moveList), "moves"},
+		{game.AbilitiesFile(), abilityList, len(abilityList), "abilities"},
+		{game.SpeciesFile(), speciesList, len(speciesList), "species"},
+		{game.EvolutionsFile(), chainList, len(chainList), "evolution chains"},
+		{game.EncountersFile(), encounterList, len(encounterList), "encounter areas"},
+		{game.ItemsFile(), itemList, len(itemList), "items"},
+		{game.MachinesFile(), machineList, len(machineList), "machines"},
+	})
+}
+
+// writeDataFiles writes every file to a temporary directory inside outDir and
+// only moves them into outDir once all of them were written.
+func writeDataFiles(outDir string, files []dataFile) error {
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return fmt.Errorf("error creating %s: %w", outDir, err)
+	}
+	tmpDir, err := os.MkdirTemp(outDir, ".ingest-")
+	if err != nil {
+		return fmt.Errorf("error creating temporary directory in %s: %w", outDir, err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	for _, f := range files {
+		if err := writeJSON(filepath.Join(tmpDir, f.name), f.data); err != nil {
+			return err
+		}
+	}
+	for _, f := range files {
+		if err := os.Rename(filepath.Join(tmpDir, f.name), filepath.Join(outDir, f.name)); err != nil {
+			return fmt.Errorf("error moving %s into %s: %w", f.name, outDir, err)
+		}
+		log.Printf("Wrote %d %s to %s", f.count, f.what, f.name)
+	}
+	return nil
+}
+
+// fetchPokemon fetches every species of the game's regional Pokédex and the
+// default form of each one, which is not always named like the species
+// (wormadam-plant, giratina-altered).
+func fetchPokemon(game games.Game, source Source) ([]pokemon.Pokemon, []map[string]interface{}, error) {
+	body, err := source.Get(fmt.Sprintf("pokedex/%d", game.PokedexID))
+	if err != nil {
+		return nil, nil, err
+	}
+	var pokedex pokemon.Pokedex
+	if err := json.Unmarshal(body, &pokedex); err != nil {
+		return nil, nil, fmt.Errorf("error decoding Pokédex %d: %w", game.PokedexID, err)
+	}
+	log.Printf("Found %d Pokémon in %s Pokédex", len(pokedex.PokemonEntries), game.Pokedex)
+
+	speciesPaths := make([]string, len(pokedex.PokemonEntries))
+	for i, entry := range pokedex.PokemonEntries {
+		speciesPaths[i] = common.ResourcePath(entry.SpeciesURL())
+	}
+	bodies, err := getAll(source, speciesPaths)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	speciesList := make([]map[string]interface{}, len(bodies))
+	paths := make([]string, len(bodies))
+	for i, body := range bodies {
+		var species pokemon.Species
+		if err := json.Unmarshal(body, &species); err != nil {
+			return nil, nil, fmt.Errorf("error decoding %s: %w", speciesPaths[i], err)
+		}
+		url, err := species.DefaultPokemonURL()
+		if err != nil {
+			return nil, nil, err
+		}
+		paths[i] = common.ResourcePath(url)
+		if err := json.Unmarshal(body, &speciesList[i]); err != nil {
+			return nil, nil, fmt.Errorf("error decoding %s: %w", speciesPaths[i], err)
+		}
+	}
+	bodies, err = getAll(source, paths)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	list := make([]pokemon.Pokemon, len(bodies))
+	for i, body := range bodies {
+		entry := pokedex.PokemonEntries[i]
+		if err := json.Unmarshal(body, &list[i]); err != nil {
+			return nil, nil, fmt.Errorf("error decoding Pokémon %s: %w", entry.PokemonSpecies.Name, err)
+		}
+		list[i].RegionalID = entry.EntryNumber
+	}
+
+	// Same order as a Firestore export (by document ID)
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	sort.Slice(speciesList, func(i, j int) bool {
+		a, _ := speciesList[i]["name"].(string)
+		b, _ := speciesList[j]["name"].(string)
+		return a < b
+	})
+	return list, speciesList, nil
+}
+
+// fetchMoves fetches every move introduced up to the game's generation.
+func fetchMoves(game games.Game, source Source) ([]map[string]interface{}, error) {
+	var list []map[string]interface{}
+	for genID := 1; genID <= game.Generation; genID++ {
+		body, err := source.Get(fmt.Sprintf("generation/%d", genID))
+		if err != nil {
+			return nil, err
+		}
+		var gen moves.GenerationMoves
+		if err := json.Unmarshal(body, &gen); err != nil {
+			return nil, fmt.Errorf("error decoding Generation %d: %w", genID, err)
+		}
+		log.Printf("Found %d moves from Generation %d", len(gen.Moves), genID)
+
+		paths := make([]string, len(gen.Moves))
+		for i, move := range gen.Moves {
+			paths[i] = common.ResourcePath(move.URL)
+		}
+		bodies, err := getAll(source, paths)
+		if err != nil {
+			return nil, err
+		}
+		for i, body := range bodies {
+			var moveData map[string]interface{}
+			if err := json.Unmarshal(body, &moveData); err != nil {
+				return nil, fmt.Errorf("error decoding move %s: %w", gen.Moves[i].Name, err)
+			}
+			list = append(list, moveData)
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		a, _ := list[i]["name"].(string)
+		b, _ := list[j]["name"].(string)
+		return a < b
+	})
+	return list, nil
+}
+
+// fetchAbilities fetches every ability the game's Pokémon can have, including
+// the ones they had before PokeAPI's past_abilities changed them (Gengar's
+// Levitate). Hidden abilities and abilities from later generations are
+// skipped, since PokeAPI lists the abilities of the latest games.
+func fetchAbilities(game games.Game, source Source, pokemonList []pokemon.Pokemon) ([]map[string]interface{}, error) {
+	seen := make(map[string]bool)
+	var paths []string
+	add := func(name, url string, hidden bool) {
+		if name == "" || seen[name] || (hidden && game.Generation < 5) {
+			return
+		}
+		seen[name] = true
+		path := "ability/" + name
+		if url != "" {
+			path = common.ResourcePath(url)
+		}
+		paths = append(paths, path)
+	}
+	for _, p := range pokemonList {
+		for _, a := range p.Abilities {
+			add(a.Ability.Name, a.Ability.URL, a.IsHidden)
+		}
+		for _, past := range p.PastAbilities {
+			if gen, err := typeeffectiveness.ParseGeneration(past.Generation.Name); err != nil || gen < game.Generation {
+				continue
+			}
+			for _, a := range past.Abilities {
+				add(a.Ability.Name, a.Ability.URL, a.IsHidden)
+			}
+		}
+	}
+
+	bodies, err := getAll(source, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []map[string]interface{}
+	for i, body := range bodies {
+		var abilityData map[string]interface{}
+		if err := json.Unmarshal(body, &abilityData); err != nil {
+			return nil, fmt.Errorf("error decoding %s: %w", paths[i], err)
+		}
+		if gen, ok := abilityData["generation"].(map[string]interface{}); ok {
+			name, _ := gen["name"].(string)
+			if n, err := typeeffectiveness.ParseGeneration(name); err == nil && n > game.Generation {
+				continue
+			}
+		}
+		list = append(list, abilityData)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		a, _ := list[i]["name"].(string)
+		b, _ := list[j]["name"].(string)
+		return a < b
+	})
+	return list, nil
+}
+
+// fetchEvolutions fetches the evolution chains of the given species. Each
+// chain is stored once.
+func fetchEvolutions(source Source, speciesList []map[string]interface{}) ([]map[string]interface{}, error) {
+	seen := make(map[string]bool)
+	var chainPaths []string
+	for _, species := range speciesList {
+		chain, _ := species["evolution_chain"].(map[string]interface{})
+		url, _ := chain["url"].(string)
+		if url == "" {
+			continue
+		}
+		path := common.ResourcePath(url)
+		if !seen[path] {
+			seen[path] = true
+			chainPaths = append(chainPaths, path)
+		}
+	}
+
+	bodies, err := getAll(source, chainPaths)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]map[string]interface{}, len(bodies))
+	for i, body := range bodies {
+		if err := json.Unmarshal(body, &list[i]); err != nil {
+			return nil, fmt.Errorf("error decoding %s: %w", chainPaths[i], err)
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		a, _ := list[i]["id"].(float64)
+		b, _ := list[j]["id"].(float64)
+		return a < b
+	})
+	return list, nil
+}
+
+// namedResource is a PokeAPI reference to another resource.
+type namedResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// fetchEncounters fetches where each Pokémon can be found in the wild. Each
+// record is one location area of one Pokémon, keeping only the details of the
+// game's versions; areas not in any of them are dropped.
+func fetchEncounters(game games.Game, source Source, pokemonList []pokemon.Pokemon) ([]map[string]interface{}, error) {
+	paths := make([]string, len(pokemonList))
+	for i, p := range pokemonList {
+		paths[i] = fmt.Sprintf("pokemon/%d/encounters", p.ID)
+		if p.LocationAreaEncounters != "" {
+			paths[i] = common.ResourcePath(p.LocationAreaEncounters)
+		}
+	}
+	bodies, err := getAll(source, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	inGame := make(map[string]bool, len(game.Versions))
+	for _, v := range game.Versions {
+		inGame[v] = true
+	}
+
+	var list []map[string]interface{}
+	for i, body := range bodies {
+		var areas []map[string]interface{}
+		if err := json.Unmarshal(body, &areas); err != nil {
+			return nil, fmt.Errorf("error decoding %s: %w", paths[i], err)
+		}
+		for _, area := range areas {
+			details, _ := area["version_details"].([]interface{})
+			var kept []interface{}
+			for _, d := range details {
+				detail, _ := d.(map[string]interface{})
+				version, _ := detail["version"].(map[string]interface{})
+				if name, _ := version["name"].(string); inGame[name] {
+					kept = append(kept, detail)
+				}
+			}
+			if len(kept) == 0 {
+				continue
+			}
+			area["version_details"] = kept
+			area["pokemon"] = pokemonList[i].Name
+			list = append(list, area)
+		}
+	}
+	return list, nil
+}
+
+// itemIndex is the part of a PokeAPI item used to decide whether it belongs
+// to a game.
+type itemIndex struct {
+	Name        string `json:"name"`
+	GameIndices []struct {
+		Generation namedResource `json:"generation"`
+	} `json:"game_indices"`
+	Machines []struct {
+		Machine      namedResource `json:"machine"`
+		VersionGroup namedResource `json:"version_group"`
+	} `json:"machines"`
+}
+
+// fetchItems fetches every item that exists in the game's generation, and the
+// machines (TM/HM to move mappings) of the game's version group.
+func fetchItems(game games.Game, source Source) (items, machines []map[string]interface{}, err error) {
+	body, err := source.Get("item?limit=100000")
+	if err != nil {
+		return nil, nil, err
+	}
+	var index struct {
+		Results []namedResource `json:"results"`
+	}
+	if err := json.Unmarshal(body, &index); err != nil {
+		return nil, nil, fmt.Errorf("error decoding item list: %w", err)
+	}
+	log.Printf("Found %d items in PokeAPI", len(index.Results))
+
+	paths := make([]string, len(index.Results))
+	for i, item := range index.Results {
+		paths[i] = common.ResourcePath(item.URL)
+	}
+	bodies, err := getAll(source, paths)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var machinePaths []string
+	for i, body := range bodies {
+		var info itemIndex
+		if err := json.Unmarshal(body, &info); err != nil {
+			return nil, nil, fmt.Errorf("error decoding item %s: %w", index.Results[i].Name, err)
+		}
+		inGame := false
+		for _, gi := range info.GameIndices {
+			if n, err := typeeffectiveness.ParseGeneration(gi.Generation.Name); err == nil && n == game.Generation {
+				inGame = true
+			}
+		}
+		if !inGame {
+			continue
+		}
+
+		var itemData map[string]interface{}
+		if err := json.Unmarshal(body, &itemData); err != nil {
+			return nil, nil, fmt.Errorf("error decoding item %s: %w", info.Name, err)
+		}
+		items = append(items, itemData)
+		for _, m := range info.Machines {
+			if m.VersionGroup.Name == game.VersionGroup {
+				machinePaths = append(machinePaths, common.ResourcePath(m.Machine.URL))
+			}
+		}
+	}
+
+	bodies, err = getAll(source, machinePaths)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, body := range bodies {
+		var machineData map[string]interface{}
+		if err := json.Unmarshal(body, &machineData); err != nil {
+			return nil, nil, fmt.Errorf("error decoding %s: %w", machinePaths[i], err)
+		}
+		machines = append(machines, machineData)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		a, _ := items[i]["name"].(string)
+		b, _ := items[j]["name"].(string)
+		return a < b
+	})
+	sort.Slice(machines, func(i, j int) bool {
+		a, _ := machines[i]["id"].(float64)
+		b, _ := machines[j]["id"].(float64)
+		return a < b
+	})
+	return items, machines, nil
+}
+
+// getAll fetches every path concurrently and returns the bodies in the same
+// order. The first error aborts the result.
+func getAll(source Source, paths []string) ([][]byte, error) {
+	bodies := make([][]byte, len(paths))
+	errs := make([]error, len(paths))
+	common.Parallel(len(paths), common.DefaultWorkers, func(i int) {
+		bodies[i], errs[i] = source.Get(paths[i])
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return bodies, nil
+}
+
+// writeJSON writes v to path through a temporary file, so a running server
+// watching data/ never sees a half-written file.
+func writeJSON(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("error marshaling %s: %w", path, err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("error writing %s: %w", tmp, err)
+	}
+	return os.Rename(tmp, path)
+}
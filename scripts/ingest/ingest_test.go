@@ -0,0 +1,145 @@
+package ingest
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"pokeproject/api"
+	"pokeproject/games"
+)
+
+// testdata/mirror is a small api-data style mirror of PokeAPI for HG/SS: a
+// Pokédex of Wooper and Wormadam, whose default form is wormadam-plant, with
+// their species, abilities, evolution chains and encounters, one move and a
+// few items. Hidden abilities are not in it, so fetching one fails the run.
+
+// readNames reads a data file written by Run and returns the value of key in
+// each document.
+func readNames(t *testing.T, path, key string) []interface{} {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var docs []map[string]interface{}
+	if err := json.Unmarshal(data, &docs); err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+	values := []interface{}{}
+	for _, doc := range docs {
+		values = append(values, doc[key])
+	}
+	return values
+}
+
+func TestRunMirror(t *testing.T) {
+	game, err := games.Lookup("heartgold-soulsilver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := t.TempDir()
+	if err := Run(game, &MirrorSource{Dir: filepath.Join("testdata", "mirror")}, out); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		file string
+		key  string
+		want []interface{}
+	}{
+		{game.PokemonFile(), "name", []interface{}{"wooper", "wormadam-plant"}},
+		{game.PokemonFile(), "regional_id", []interface{}{57.0, 58.0}},
+		{game.MovesFile(), "name", []interface{}{"tackle"}},
+		{game.AbilitiesFile(), "name", []interface{}{"anticipation", "damp", "water-absorb"}},
+		{game.SpeciesFile(), "name", []interface{}{"wooper", "wormadam"}},
+		{game.EvolutionsFile(), "id", []interface{}{96.0, 208.0}},
+		// The Platinum-only area is dropped
+		{game.EncountersFile(), "pokemon", []interface{}{"wooper"}},
+		// Assault Vest is not in Gen IV
+		{game.ItemsFile(), "name", []interface{}{"poke-ball", "tm26"}},
+		{game.MachinesFile(), "id", []interface{}{258.0}},
+	}
+	for _, tt := range tests {
+		got := readNames(t, filepath.Join(out, tt.file), tt.key)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s %s: got %v, want %v", tt.file, tt.key, got, tt.want)
+		}
+	}
+
+	// Encounter details of other versions are dropped
+	data, err := os.ReadFile(filepath.Join(out, game.EncountersFile()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var encounters []struct {
+		VersionDetails []struct {
+			Version struct {
+				Name string `json:"name"`
+			} `json:"version"`
+		} `json:"version_details"`
+	}
+	if err := json.Unmarshal(data, &encounters); err != nil {
+		t.Fatal(err)
+	}
+	var versions []string
+	for _, vd := range encounters[0].VersionDetails {
+		versions = append(versions, vd.Version.Name)
+	}
+	if want := []string{"heartgold", "soulsilver"}; !reflect.DeepEqual(versions, want) {
+		t.Errorf("encounter versions: got %v, want %v", versions, want)
+	}
+
+	// The server loads what was written
+	cache, err := api.NewCacheFromJSON(out, game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := cache.PokemonByName("wooper"); p == nil || len(p.Abilities) != 2 {
+		t.Errorf("wooper: got %+v, want 2 abilities without the hidden one", p)
+	}
+}
+
+func TestRunMirrorMissingKeepsFiles(t *testing.T) {
+	game, err := games.Lookup("heartgold-soulsilver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := t.TempDir()
+	existing := filepath.Join(out, game.PokemonFile())
+	if err := os.WriteFile(existing, []byte("[]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The Pokédex and species are found but the Pokemon are not
+	mirror := t.TempDir()
+	for _, path := range []string{"pokedex/7", "pokemon-species/194", "pokemon-species/413"} {
+		data, err := os.ReadFile(filepath.Join("testdata", "mirror", path, "index.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		dir := filepath.Join(mirror, path)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "index.json"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := Run(game, &MirrorSource{Dir: mirror}, out); err == nil {
+		t.Fatal("got no error from an incomplete mirror")
+	}
+
+	entries, err := os.ReadDir(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("got %d entries in the output directory, want only the existing file", len(entries))
+	}
+	if data, err := os.ReadFile(existing); err != nil || string(data) != "[]" {
+		t.Errorf("existing file changed: %q, %v", data, err)
+	}
+}
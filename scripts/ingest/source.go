@@ -0,0 +1,56 @@
+package ingest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"pokeproject/scripts/common"
+)
+
+// Source returns raw PokeAPI responses for a resource path such as
+// "pokedex/7" or "move/89". Implementations must be safe for concurrent use.
+type Source interface {
+	Get(path string) ([]byte, error)
+}
+
+// FetcherSource fetches resources from the live PokeAPI through a shared
+// Fetcher, so ingest runs are rate limited, retried and resumable.
+type FetcherSource struct {
+	Fetcher *common.Fetcher
+}
+
+// Get fetches a resource from PokeAPI.
+func (s *FetcherSource) Get(path string) ([]byte, error) {
+	return s.Fetcher.Get(common.PokeAPIBaseURL + path)
+}
+
+// MirrorSource reads saved PokeAPI responses from a local directory laid out
+// like common.MirrorPath: the data/api/v2 directory of an api-data checkout,
+// or the -cache directory of an earlier run. A resource path such as "move/89"
+// is read from <Dir>/move/89/index.json, or from <Dir>/move/89.json for small
+// hand-written fixtures. Ingest requests resources by the URLs PokeAPI
+// returns, which are keyed by numeric ID like api-data.
+type MirrorSource struct {
+	Dir string
+}
+
+// Get reads a resource from the mirror directory.
+func (s *MirrorSource) Get(path string) ([]byte, error) {
+	flat, _, _ := strings.Cut(path, "?")
+	candidates := []string{
+		common.MirrorPath(s.Dir, path),
+		filepath.Join(s.Dir, filepath.FromSlash(strings.Trim(flat, "/"))+".json"),
+	}
+	for _, candidate := range candidates {
+		data, err := os.ReadFile(candidate)
+		if err == nil {
+			return data, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("error reading %s: %w", candidate, err)
+		}
+	}
+	return nil, fmt.Errorf("%s not found in mirror %s", path, s.Dir)
+}
@@ -4,13 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
 	"os"
 	"path/filepath"
 	"time"
 
+	"pokeproject/games"
 	"pokeproject/scripts/common"
 
 	"cloud.google.com/go/firestore"
@@ -57,22 +56,22 @@ type GenerationMoves struct {
 	} `json:"moves"`
 }
 
-func fetchGenerationMoves(genID int) ([]struct{ Name string `json:"name"`; URL string `json:"url"` }, error) {
-	resp, err := http.Get(fmt.Sprintf("https://pokeapi.co/api/v2/generation/%d", genID))
+func fetchGenerationMoves(fetcher *common.Fetcher, genID int) ([]struct{ Name string `json:"name"`; URL string `json:"url"` }, error) {
+	body, err := fetcher.Get(fmt.Sprintf("%sgeneration/%d", common.PokeAPIBaseURL, genID))
 	if err != nil {
 		return nil, fmt.Errorf("error fetching Generation %d: %v", genID, err)
 	}
-	defer resp.Body.Close()
 
 	var genMoves GenerationMoves
-	if err := json.NewDecoder(resp.Body).Decode(&genMoves); err != nil {
+	if err := json.Unmarshal(body, &genMoves); err != nil {
 		return nil, fmt.Errorf("error decoding Generation %d: %v", genID, err)
 	}
 
 	return genMoves.Moves, nil
 }
 
-func PopulateMoves() {
+// PopulateMoves stores every move introduced up to the game's generation in Firestore
+func PopulateMoves(game games.Game, fetcher *common.Fetcher) {
 	projectRoot := common.GetProjectRoot()
 	fmt.Println("Using directory:", projectRoot)
 
@@ -105,20 +104,20 @@ func PopulateMoves() {
 		"test": true,
 		"timestamp": time.Now(),
 	}
-	_, err = client.Collection("heartgold-moves").Doc("_test").Set(ctx, testDoc)
+	_, err = client.Collection(game.MovesCollection()).Doc("_test").Set(ctx, testDoc)
 	if err != nil {
 		log.Fatalf("Error creating test document: %v. Please ensure you have created the Firestore database in the Google Cloud Console.", err)
 	}
 	// Delete test document
-	_, err = client.Collection("heartgold-moves").Doc("_test").Delete(ctx)
+	_, err = client.Collection(game.MovesCollection()).Doc("_test").Delete(ctx)
 	if err != nil {
 		log.Printf("Warning: Could not delete test document: %v", err)
 	}
 
-	// Fetch moves from all generations up to 4
+	// Fetch moves from all generations up to the game's
 	var allMoves []struct{ Name string `json:"name"`; URL string `json:"url"` }
-	for genID := 1; genID <= 4; genID++ {
-		moves, err := fetchGenerationMoves(genID)
+	for genID := 1; genID <= game.Generation; genID++ {
+		moves, err := fetchGenerationMoves(fetcher, genID)
 		if err != nil {
 			log.Printf("Warning: %v", err)
 			continue
@@ -129,39 +128,32 @@ func PopulateMoves() {
 
 	fmt.Printf("Total moves to process: %d\n", len(allMoves))
 
-	// Process each move
-	for _, move := range allMoves {
-		// Fetch detailed move data
-		resp, err := http.Get(move.URL)
-		if err != nil {
-			log.Printf("Error fetching move %s: %v", move.Name, err)
-			continue
-		}
+	urls := make([]string, len(allMoves))
+	for i, move := range allMoves {
+		urls[i] = move.URL
+	}
 
-		// Read the entire response body
-		body, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
+	// Process each move as soon as it is fetched
+	fetcher.FetchAll(urls, func(i int, body []byte, err error) {
+		move := allMoves[i]
 		if err != nil {
-			log.Printf("Error reading response for %s: %v", move.Name, err)
-			continue
+			log.Printf("Error fetching move %s: %v", move.Name, err)
+			return
 		}
 
 		// Parse the JSON into a generic map to store all fields
 		var moveData map[string]interface{}
 		if err := json.Unmarshal(body, &moveData); err != nil {
 			log.Printf("Error decoding JSON for %s: %v", move.Name, err)
-			continue
+			return
 		}
 
 		// Store the complete data in Firestore
-		_, err = client.Collection("heartgold-moves").Doc(move.Name).Set(ctx, moveData)
+		_, err = client.Collection(game.MovesCollection()).Doc(move.Name).Set(ctx, moveData)
 		if err != nil {
 			log.Printf("Error storing %s in Firestore: %v", move.Name, err)
-			continue
+			return
 		}
 		fmt.Printf("Stored %s in Firestore\n", move.Name)
-
-		// Rate limiting to avoid overwhelming the API
-		time.Sleep(1 * time.Second)
-	}
-} 
\ No newline at end of file
+	})
+}
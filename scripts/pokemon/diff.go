@@ -0,0 +1,109 @@
+package pokemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Diff describes how freshly fetched Pokémon differ from the stored documents.
+type Diff struct {
+	Added     []string
+	Changed   []string
+	Removed   []string
+	Unchanged int
+}
+
+// diffPokemon compares stored documents with freshly fetched Pokémon, both
+// keyed by document ID.
+func diffPokemon(stored, fetched map[string]Pokemon) Diff {
+	var d Diff
+	for id, p := range fetched {
+		old, ok := stored[id]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, id)
+		case !samePokemon(old, p):
+			d.Changed = append(d.Changed, id)
+		default:
+			d.Unchanged++
+		}
+	}
+	for id := range stored {
+		if _, ok := fetched[id]; !ok {
+			d.Removed = append(d.Removed, id)
+		}
+	}
+	sort.Strings(d.Added)
+	sort.Strings(d.Changed)
+	sort.Strings(d.Removed)
+	return d
+}
+
+// Print writes a human-readable summary of the diff.
+func (d Diff) Print() {
+	fmt.Printf("Added: %d, changed: %d, removed: %d, unchanged: %d\n",
+		len(d.Added), len(d.Changed), len(d.Removed), d.Unchanged)
+	for _, id := range d.Added {
+		fmt.Println("  + " + id)
+	}
+	for _, id := range d.Changed {
+		fmt.Println("  ~ " + id)
+	}
+	for _, id := range d.Removed {
+		fmt.Println("  - " + id)
+	}
+}
+
+// samePokemon compares two Pokémon by content. Firestore does not round-trip
+// nil and empty slices exactly, so both are compared in a canonical JSON form
+// where empty values are dropped.
+func samePokemon(a, b Pokemon) bool {
+	return reflect.DeepEqual(canonical(a), canonical(b))
+}
+
+func canonical(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil
+	}
+	return dropEmpty(generic)
+}
+
+// dropEmpty removes nulls, empty strings, empty arrays and empty objects.
+func dropEmpty(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{})
+		for k, child := range val {
+			if c := dropEmpty(child); c != nil {
+				out[k] = c
+			}
+		}
+		if len(out) == 0 {
+			return nil
+		}
+		return out
+	case []interface{}:
+		var out []interface{}
+		for _, child := range val {
+			out = append(out, dropEmpty(child))
+		}
+		if len(out) == 0 {
+			return nil
+		}
+		return out
+	case string:
+		if val == "" {
+			return nil
+		}
+		return val
+	default:
+		return val
+	}
+}
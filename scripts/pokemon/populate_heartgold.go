@@ -4,13 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
+	"pokeproject/games"
 	"pokeproject/scripts/common"
 
 	"cloud.google.com/go/firestore"
@@ -45,6 +45,20 @@ type Pokemon struct {
 			URL  string `json:"url"`
 		} `json:"ability"`
 	} `json:"abilities"`
+	PastAbilities []struct {
+		Generation struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"generation"`
+		Abilities []struct {
+			IsHidden bool `json:"is_hidden"`
+			Slot     int  `json:"slot"`
+			Ability  struct {
+				Name string `json:"name"`
+				URL  string `json:"url"`
+			} `json:"ability"`
+		} `json:"abilities"`
+	} `json:"past_abilities"`
 	Forms []struct {
 		Name string `json:"name"`
 		URL  string `json:"url"`
@@ -284,7 +298,7 @@ type Pokemon struct {
 			URL  string `json:"url"`
 		} `json:"stat"`
 	} `json:"stats"`
-	RegionalID int `json:"regional_id,omitempty"` // ID en la Pokédex de Johto
+	RegionalID int `json:"regional_id,omitempty"` // ID en la Pokédex regional del juego
 }
 
 type PokedexEntry struct {
@@ -299,7 +313,48 @@ type Pokedex struct {
 	PokemonEntries []PokedexEntry `json:"pokemon_entries"`
 }
 
-func PopulateHeartgold() {
+// Species is the part of a pokemon-species response that lists its forms.
+type Species struct {
+	Name      string `json:"name"`
+	Varieties []struct {
+		IsDefault bool `json:"is_default"`
+		Pokemon   struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"pokemon"`
+	} `json:"varieties"`
+}
+
+// SpeciesURL returns the pokemon-species URL of a Pokédex entry.
+func (e PokedexEntry) SpeciesURL() string {
+	if e.PokemonSpecies.URL != "" {
+		return e.PokemonSpecies.URL
+	}
+	return fmt.Sprintf("%spokemon-species/%s/", common.PokeAPIBaseURL, e.PokemonSpecies.Name)
+}
+
+// DefaultPokemonURL returns the URL of the species' default form, which is
+// not always named like the species (wormadam-plant, giratina-altered).
+func (s Species) DefaultPokemonURL() (string, error) {
+	for _, v := range s.Varieties {
+		if !v.IsDefault {
+			continue
+		}
+		if v.Pokemon.URL != "" {
+			return v.Pokemon.URL, nil
+		}
+		return fmt.Sprintf("%spokemon/%s/", common.PokeAPIBaseURL, v.Pokemon.Name), nil
+	}
+	return "", fmt.Errorf("species %s has no default variety", s.Name)
+}
+
+// Populate stores every Pokémon of the game's regional Pokédex in Firestore.
+//
+// The update is non-destructive: everything is fetched first, then compared with
+// the stored documents. New and changed Pokémon are written, and stale documents
+// are only removed when the whole fetch succeeded. With dryRun the diff is
+// printed and nothing is written.
+func Populate(game games.Game, fetcher *common.Fetcher, dryRun bool) {
 	projectRoot := common.GetProjectRoot()
 	fmt.Println("Using directory:", projectRoot)
 
@@ -321,96 +376,157 @@ func PopulateHeartgold() {
 		log.Fatalf("Error initializing Firestore client: %v", err)
 	}
 	defer client.Close()
+	collection := client.Collection(game.PokemonCollection())
 
 	// Ensure database exists
 	if err := common.EnsureDatabaseExists(ctx, client); err != nil {
 		log.Printf("Warning: Could not verify database existence: %v", err)
 	}
 
-	// Clean the collection before populating
-	log.Println("Cleaning Pokemon collection...")
-	iter := client.Collection("heartgold-pokemon").Documents(ctx)
-	defer iter.Stop()
-
-	for {
-		doc, err := iter.Next()
+	if !dryRun {
+		// Create a test document to ensure database is working
+		testDoc := map[string]interface{}{
+			"test":      true,
+			"timestamp": time.Now(),
+		}
+		_, err = collection.Doc("_test").Set(ctx, testDoc)
 		if err != nil {
-			break
+			log.Fatalf("Error creating test document: %v. Please ensure you have created the Firestore database in the Google Cloud Console.", err)
 		}
-		_, err = doc.Ref.Delete(ctx)
+		// Delete test document
+		_, err = collection.Doc("_test").Delete(ctx)
 		if err != nil {
-			log.Printf("Error deleting document %s: %v", doc.Ref.ID, err)
+			log.Printf("Warning: Could not delete test document: %v", err)
+		}
+	}
+
+	// The diff must be against current PokeAPI data, not what an earlier run
+	// cached. The new responses still replace the cached ones.
+	fetcher.Refresh = true
+	fetched, complete := fetchPokedex(game, fetcher)
+
+	// Load the stored documents to compare against
+	docs, err := collection.Documents(ctx).GetAll()
+	if err != nil {
+		log.Fatalf("Error reading %s: %v", game.PokemonCollection(), err)
+	}
+	stored := make(map[string]Pokemon)
+	for _, doc := range docs {
+		if doc.Ref.ID == "_test" {
 			continue
 		}
+		var p Pokemon
+		if err := doc.DataTo(&p); err != nil {
+			log.Printf("Warning: could not decode stored document %s, it will be rewritten: %v", doc.Ref.ID, err)
+		}
+		stored[doc.Ref.ID] = p
 	}
-	log.Println("Collection cleaned successfully")
 
-	// Create a test document to ensure database is working
-	testDoc := map[string]interface{}{
-		"test": true,
-		"timestamp": time.Now(),
+	diff := diffPokemon(stored, fetched)
+	if !complete {
+		// Documents missing from a partial fetch are not known to be stale
+		diff.Removed = nil
 	}
-	_, err = client.Collection("heartgold-pokemon").Doc("_test").Set(ctx, testDoc)
-	if err != nil {
-		log.Fatalf("Error creating test document: %v. Please ensure you have created the Firestore database in the Google Cloud Console.", err)
+	diff.Print()
+
+	if dryRun {
+		fmt.Println("Dry run: nothing was written")
+		return
 	}
-	// Delete test document
-	_, err = client.Collection("heartgold-pokemon").Doc("_test").Delete(ctx)
-	if err != nil {
-		log.Printf("Warning: Could not delete test document: %v", err)
+
+	for _, id := range append(append([]string{}, diff.Added...), diff.Changed...) {
+		p := fetched[id]
+		if _, err := collection.Doc(id).Set(ctx, p); err != nil {
+			log.Printf("Error storing %s in Firestore: %v", id, err)
+			continue
+		}
+		fmt.Printf("Stored %s (Regional ID: %d) in Firestore\n", id, p.RegionalID)
 	}
 
-	// Fetch Johto Pokédex entries
-	resp, err := http.Get("https://pokeapi.co/api/v2/pokedex/7")
+	if !complete {
+		log.Println("Warning: some Pokémon could not be fetched, stale documents were kept")
+		return
+	}
+	for _, id := range diff.Removed {
+		if _, err := collection.Doc(id).Delete(ctx); err != nil {
+			log.Printf("Error deleting document %s: %v", id, err)
+			continue
+		}
+		fmt.Printf("Removed stale %s from Firestore\n", id)
+	}
+}
+
+// fetchPokedex fetches every Pokémon of the game's regional Pokédex, keyed by
+// document ID. complete is false if any Pokémon could not be fetched.
+func fetchPokedex(game games.Game, fetcher *common.Fetcher) (fetched map[string]Pokemon, complete bool) {
+	// Fetch the regional Pokédex entries
+	body, err := fetcher.Get(fmt.Sprintf("%spokedex/%d", common.PokeAPIBaseURL, game.PokedexID))
 	if err != nil {
 		log.Fatalf("Error fetching Pokédex: %v", err)
 	}
-	defer resp.Body.Close()
 
 	var pokedex Pokedex
-	if err := json.NewDecoder(resp.Body).Decode(&pokedex); err != nil {
+	if err := json.Unmarshal(body, &pokedex); err != nil {
 		log.Fatalf("Error decoding Pokédex response: %v", err)
 	}
 
-	fmt.Printf("Found %d Pokémon in Johto Pokédex\n", len(pokedex.PokemonEntries))
+	fmt.Printf("Found %d Pokémon in %s Pokédex\n", len(pokedex.PokemonEntries), game.Pokedex)
 
-	// Process each Pokémon
-	for _, entry := range pokedex.PokemonEntries {
-		// Fetch detailed Pokémon data using the /pokemon/{name} endpoint
-		pokemonURL := fmt.Sprintf("https://pokeapi.co/api/v2/pokemon/%s", entry.PokemonSpecies.Name)
-		resp, err := http.Get(pokemonURL)
+	// Resolve each species to the URL of its default form
+	speciesURLs := make([]string, len(pokedex.PokemonEntries))
+	for i, entry := range pokedex.PokemonEntries {
+		speciesURLs[i] = entry.SpeciesURL()
+	}
+
+	var mu sync.Mutex
+	complete = true
+	var urls []string
+	var entries []PokedexEntry
+	fetcher.FetchAll(speciesURLs, func(i int, body []byte, err error) {
+		entry := pokedex.PokemonEntries[i]
+		var species Species
+		if err == nil {
+			err = json.Unmarshal(body, &species)
+		}
+		var url string
+		if err == nil {
+			url, err = species.DefaultPokemonURL()
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
 		if err != nil {
-			log.Printf("Error fetching %s: %v", entry.PokemonSpecies.Name, err)
-			continue
+			log.Printf("Error fetching species %s: %v", entry.PokemonSpecies.Name, err)
+			complete = false
+			return
 		}
+		urls = append(urls, url)
+		entries = append(entries, entry)
+	})
+
+	// Fetch detailed Pokémon data using the /pokemon/{id} endpoint
+	fetched = make(map[string]Pokemon)
+	fetcher.FetchAll(urls, func(i int, body []byte, err error) {
+		entry := entries[i]
+		mu.Lock()
+		defer mu.Unlock()
 
-		// Read the entire response body
-		body, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
 		if err != nil {
-			log.Printf("Error reading response for %s: %v", entry.PokemonSpecies.Name, err)
-			continue
+			log.Printf("Error fetching %s: %v", entry.PokemonSpecies.Name, err)
+			complete = false
+			return
 		}
 
-		// Parse the JSON into a generic map to store all fields
 		var pokemon Pokemon
 		if err := json.Unmarshal(body, &pokemon); err != nil {
 			log.Printf("Error decoding JSON for %s: %v", entry.PokemonSpecies.Name, err)
-			continue
+			complete = false
+			return
 		}
 
 		// Add the regional ID from the Pokédex entry
 		pokemon.RegionalID = entry.EntryNumber
-
-		// Store the complete data in Firestore
-		_, err = client.Collection("heartgold-pokemon").Doc(entry.PokemonSpecies.Name).Set(ctx, pokemon)
-		if err != nil {
-			log.Printf("Error storing %s in Firestore: %v", entry.PokemonSpecies.Name, err)
-			continue
-		}
-		fmt.Printf("Stored %s (Regional ID: %d) in Firestore\n", entry.PokemonSpecies.Name, entry.EntryNumber)
-
-		// Rate limiting to avoid overwhelming the API
-		time.Sleep(1 * time.Second)
-	}
-} 
\ No newline at end of file
+		fetched[entry.PokemonSpecies.Name] = pokemon
+	})
+	return fetched, complete
+}
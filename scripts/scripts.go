@@ -1,22 +1,42 @@
 package scripts
 
 import (
+	"log"
+
+	"pokeproject/games"
+	"pokeproject/scripts/common"
 	"pokeproject/scripts/export"
+	"pokeproject/scripts/ingest"
 	"pokeproject/scripts/moves"
 	"pokeproject/scripts/pokemon"
 )
 
-// PopulateHeartgold runs the Pokémon population script
-func PopulateHeartgold() {
-	pokemon.PopulateHeartgold()
+// PopulatePokemon runs the Pokémon population script for a game.
+// PokeAPI responses are cached in cacheDir ("" disables the cache). With
+// dryRun the changes are only printed.
+func PopulatePokemon(game games.Game, cacheDir string, dryRun bool) {
+	pokemon.Populate(game, common.NewFetcher(cacheDir), dryRun)
+}
+
+// PopulateMoves runs the moves population script for a game.
+// PokeAPI responses are cached in cacheDir ("" disables the cache).
+func PopulateMoves(game games.Game, cacheDir string) {
+	moves.PopulateMoves(game, common.NewFetcher(cacheDir))
 }
 
-// PopulateMoves runs the moves population script
-func PopulateMoves() {
-	moves.PopulateMoves()
+// ExportJSON exports a game's Firestore data to local JSON files
+func ExportJSON(game games.Game) {
+	export.ExportJSON(game)
 }
 
-// ExportJSON exports Firestore data to local JSON files
-func ExportJSON() {
-	export.ExportJSON()
-} 
\ No newline at end of file
+// Ingest builds a game's data/ JSON files straight from PokeAPI, or from a
+// directory of saved responses when mirrorDir is set. No Firestore needed.
+func Ingest(game games.Game, mirrorDir, outDir, cacheDir string) {
+	var source ingest.Source = &ingest.FetcherSource{Fetcher: common.NewFetcher(cacheDir)}
+	if mirrorDir != "" {
+		source = &ingest.MirrorSource{Dir: mirrorDir}
+	}
+	if err := ingest.Run(game, source, outDir); err != nil {
+		log.Fatalf("Ingest failed: %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Nature raises one stat by 10% and lowers another by 10%. Neutral natures
+// raise and lower the same stat, which cancels out.
+type Nature struct {
+	Name      string `json:"name"`
+	Increased string `json:"increased"`
+	Decreased string `json:"decreased"`
+	// Names maps a language code to the localized nature name.
+	Names map[string]string `json:"names"`
+}
+
+// Modifier returns the multiplier the nature applies to a stat.
+func (n Nature) Modifier(stat string) float64 {
+	if n.Increased == n.Decreased {
+		return 1
+	}
+	switch stat {
+	case n.Increased:
+		return 1.1
+	case n.Decreased:
+		return 0.9
+	}
+	return 1
+}
+
+// Modifiers returns the multiplier the nature applies to each stat except HP.
+func (n Nature) Modifiers() map[string]float64 {
+	mods := make(map[string]float64, len(Names)-1)
+	for _, name := range Names[1:] {
+		mods[name] = n.Modifier(name)
+	}
+	return mods
+}
+
+// Neutral reports whether the nature has no effect on stats.
+func (n Nature) Neutral() bool {
+	return n.Increased == n.Decreased
+}
+
+// DisplayName returns the nature name in the given language, falling back to
+// English.
+func (n Nature) DisplayName(lang string) string {
+	if name, ok := n.Names[lang]; ok {
+		return name
+	}
+	return n.Names["en"]
+}
+
+// Natures lists the 25 natures in index order.
+var Natures = []Nature{
+	{Name: "hardy", Increased: "attack", Decreased: "attack", Names: map[string]string{
+		"en": "Hardy", "es": "Fuerte", "fr": "Hardi", "de": "Robust", "it": "Ardita",
+	}},
+	{Name: "lonely", Increased: "attack", Decreased: "defense", Names: map[string]string{
+		"en": "Lonely", "es": "Huraña", "fr": "Solo", "de": "Solo", "it": "Schiva",
+	}},
+	{Name: "brave", Increased: "attack", Decreased: "speed", Names: map[string]string{
+		"en": "Brave", "es": "Audaz", "fr": "Brave", "de": "Mutig", "it": "Audace",
+	}},
+	{Name: "adamant", Increased: "attack", Decreased: "special-attack", Names: map[string]string{
+		"en": "Adamant", "es": "Firme", "fr": "Rigide", "de": "Hart", "it": "Decisa",
+	}},
+	{Name: "naughty", Increased: "attack", Decreased: "special-defense", Names: map[string]string{
+		"en": "Naughty", "es": "Pícara", "fr": "Mauvais", "de": "Frech", "it": "Birbona",
+	}},
+	{Name: "bold", Increased: "defense", Decreased: "attack", Names: map[string]string{
+		"en": "Bold", "es": "Osada", "fr": "Assuré", "de": "Kühn", "it": "Sicura",
+	}},
+	{Name: "docile", Increased: "defense", Decreased: "defense", Names: map[string]string{
+		"en": "Docile", "es": "Dócil", "fr": "Docile", "de": "Sanft", "it": "Docile",
+	}},
+	{Name: "relaxed", Increased: "defense", Decreased: "speed", Names: map[string]string{
+		"en": "Relaxed", "es": "Plácida", "fr": "Relax", "de": "Locker", "it": "Placida",
+	}},
+	{Name: "impish", Increased: "defense", Decreased: "special-attack", Names: map[string]string{
+		"en": "Impish", "es": "Agitada", "fr": "Malin", "de": "Pfiffig", "it": "Scaltra",
+	}},
+	{Name: "lax", Increased: "defense", Decreased: "special-defense", Names: map[string]string{
+		"en": "Lax", "es": "Floja", "fr": "Lâche", "de": "Lasch", "it": "Fiacca",
+	}},
+	{Name: "timid", Increased: "speed", Decreased: "attack", Names: map[string]string{
+		"en": "Timid", "es": "Miedosa", "fr": "Timide", "de": "Scheu", "it": "Timida",
+	}},
+	{Name: "hasty", Increased: "speed", Decreased: "defense", Names: map[string]string{
+		"en": "Hasty", "es": "Activa", "fr": "Pressé", "de": "Hastig", "it": "Lesta",
+	}},
+	{Name: "serious", Increased: "speed", Decreased: "speed", Names: map[string]string{
+		"en": "Serious", "es": "Seria", "fr": "Sérieux", "de": "Ernst", "it": "Seria",
+	}},
+	{Name: "jolly", Increased: "speed", Decreased: "special-attack", Names: map[string]string{
+		"en": "Jolly", "es": "Alegre", "fr": "Jovial", "de": "Froh", "it": "Allegra",
+	}},
+	{Name: "naive", Increased: "speed", Decreased: "special-defense", Names: map[string]string{
+		"en": "Naive", "es": "Ingenua", "fr": "Naïf", "de": "Naiv", "it": "Ingenua",
+	}},
+	{Name: "modest", Increased: "special-attack", Decreased: "attack", Names: map[string]string{
+		"en": "Modest", "es": "Modesta", "fr": "Modeste", "de": "Mäßig", "it": "Modesta",
+	}},
+	{Name: "mild", Increased: "special-attack", Decreased: "defense", Names: map[string]string{
+		"en": "Mild", "es": "Afable", "fr": "Doux", "de": "Mild", "it": "Mite",
+	}},
+	{Name: "quiet", Increased: "special-attack", Decreased: "speed", Names: map[string]string{
+		"en": "Quiet", "es": "Mansa", "fr": "Discret", "de": "Ruhig", "it": "Quieta",
+	}},
+	{Name: "bashful", Increased: "special-attack", Decreased: "special-attack", Names: map[string]string{
+		"en": "Bashful", "es": "Tímida", "fr": "Pudique", "de": "Zaghaft", "it": "Ritrosa",
+	}},
+	{Name: "rash", Increased: "special-attack", Decreased: "special-defense", Names: map[string]string{
+		"en": "Rash", "es": "Alocada", "fr": "Foufou", "de": "Hitzig", "it": "Ardente",
+	}},
+	{Name: "calm", Increased: "special-defense", Decreased: "attack", Names: map[string]string{
+		"en": "Calm", "es": "Serena", "fr": "Calme", "de": "Still", "it": "Calma",
+	}},
+	{Name: "gentle", Increased: "special-defense", Decreased: "defense", Names: map[string]string{
+		"en": "Gentle", "es": "Amable", "fr": "Gentil", "de": "Zart", "it": "Gentile",
+	}},
+	{Name: "sassy", Increased: "special-defense", Decreased: "speed", Names: map[string]string{
+		"en": "Sassy", "es": "Grosera", "fr": "Malpoli", "de": "Forsch", "it": "Vivace",
+	}},
+	{Name: "careful", Increased: "special-defense", Decreased: "special-attack", Names: map[string]string{
+		"en": "Careful", "es": "Cauta", "fr": "Prudent", "de": "Sacht", "it": "Cauta",
+	}},
+	{Name: "quirky", Increased: "special-defense", Decreased: "special-defense", Names: map[string]string{
+		"en": "Quirky", "es": "Rara", "fr": "Bizarre", "de": "Kauzig", "it": "Furba",
+	}},
+}
+
+// DefaultNature is the neutral nature used when none is given.
+var DefaultNature = Natures[0]
+
+// LookupNature finds a nature by English or localized name. An empty name
+// gives DefaultNature.
+func LookupNature(name string) (Nature, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return DefaultNature, nil
+	}
+	for _, n := range Natures {
+		if n.Name == name {
+			return n, nil
+		}
+	}
+	for _, n := range Natures {
+		for _, localized := range n.Names {
+			if strings.ToLower(localized) == name {
+				return n, nil
+			}
+		}
+	}
+	return Nature{}, fmt.Errorf("unknown nature: %s", name)
+}
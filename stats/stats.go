@@ -0,0 +1,174 @@
+// Package stats computes in-game stats from base stats, level, IVs, EVs and
+// nature using the formulas of Generation III onwards.
+package stats
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Limits of individual and effort values.
+const (
+	MaxIV       = 31
+	MaxEV       = 252
+	MaxTotalEVs = 510
+)
+
+// Names lists the six stats in PokeAPI order.
+var Names = []string{"hp", "attack", "defense", "special-attack", "special-defense", "speed"}
+
+// Stats holds a value for each of the six stats.
+type Stats struct {
+	HP             int `json:"hp"`
+	Attack         int `json:"attack"`
+	Defense        int `json:"defense"`
+	SpecialAttack  int `json:"special-attack"`
+	SpecialDefense int `json:"special-defense"`
+	Speed          int `json:"speed"`
+}
+
+// Uniform returns Stats with every stat set to v.
+func Uniform(v int) Stats {
+	return Stats{v, v, v, v, v, v}
+}
+
+// Get returns the value of a stat by PokeAPI name, or 0 for an unknown name.
+func (s Stats) Get(name string) int {
+	if p := s.field(name); p != nil {
+		return *p
+	}
+	return 0
+}
+
+// Set sets the value of a stat by PokeAPI name. Unknown names are ignored.
+func (s *Stats) Set(name string, v int) {
+	if p := s.field(name); p != nil {
+		*p = v
+	}
+}
+
+// Total returns the sum of the six stats.
+func (s Stats) Total() int {
+	return s.HP + s.Attack + s.Defense + s.SpecialAttack + s.SpecialDefense + s.Speed
+}
+
+func (s *Stats) field(name string) *int {
+	switch name {
+	case "hp":
+		return &s.HP
+	case "attack":
+		return &s.Attack
+	case "defense":
+		return &s.Defense
+	case "special-attack":
+		return &s.SpecialAttack
+	case "special-defense":
+		return &s.SpecialDefense
+	case "speed":
+		return &s.Speed
+	}
+	return nil
+}
+
+// Stat computes a single stat. modifier is the nature multiplier (0.9, 1 or
+// 1.1) and is ignored for HP. A base HP of 1 (Shedinja) always gives 1 HP.
+func Stat(name string, base, level, iv, ev int, modifier float64) int {
+	v := (2*base + iv + ev/4) * level / 100
+	if name == "hp" {
+		if base == 1 {
+			return 1
+		}
+		return v + level + 10
+	}
+	v += 5
+	switch {
+	case modifier > 1:
+		return v * 110 / 100
+	case modifier < 1:
+		return v * 90 / 100
+	}
+	return v
+}
+
+// Calculate computes all six stats.
+func Calculate(base Stats, level int, ivs, evs Stats, nature Nature) Stats {
+	var out Stats
+	for _, name := range Names {
+		out.Set(name, Stat(name, base.Get(name), level, ivs.Get(name), evs.Get(name), nature.Modifier(name)))
+	}
+	return out
+}
+
+// Range computes the lowest and highest value each stat can have at a level:
+// 0 IVs, 0 EVs and a hindering nature against 31 IVs, 252 EVs and a
+// beneficial nature.
+func Range(base Stats, level int) (low, high Stats) {
+	for _, name := range Names {
+		low.Set(name, Stat(name, base.Get(name), level, 0, 0, 0.9))
+		high.Set(name, Stat(name, base.Get(name), level, MaxIV, MaxEV, 1.1))
+	}
+	return low, high
+}
+
+// ValidateLevel checks that a level is between 1 and 100.
+func ValidateLevel(level int) error {
+	if level < 1 || level > 100 {
+		return fmt.Errorf("level must be between 1 and 100, got %d", level)
+	}
+	return nil
+}
+
+// ValidateIVs checks that every IV is between 0 and 31.
+func ValidateIVs(ivs Stats) error {
+	for _, name := range Names {
+		if v := ivs.Get(name); v < 0 || v > MaxIV {
+			return fmt.Errorf("%s IV must be between 0 and %d, got %d", name, MaxIV, v)
+		}
+	}
+	return nil
+}
+
+// ValidateEVs checks that every EV is between 0 and 252 and that they add up
+// to at most 510.
+func ValidateEVs(evs Stats) error {
+	for _, name := range Names {
+		if v := evs.Get(name); v < 0 || v > MaxEV {
+			return fmt.Errorf("%s EV must be between 0 and %d, got %d", name, MaxEV, v)
+		}
+	}
+	if total := evs.Total(); total > MaxTotalEVs {
+		return fmt.Errorf("EVs must add up to at most %d, got %d", MaxTotalEVs, total)
+	}
+	return nil
+}
+
+// ParseSpread parses IVs or EVs given either as a single value for every stat
+// ("31") or as six comma-separated values in PokeAPI order
+// ("252,0,0,0,4,252"). An empty string gives def for every stat.
+func ParseSpread(s string, def int) (Stats, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Uniform(def), nil
+	}
+	parts := strings.Split(s, ",")
+	if len(parts) != 1 && len(parts) != len(Names) {
+		return Stats{}, fmt.Errorf("expected 1 or %d comma-separated values, got %d", len(Names), len(parts))
+	}
+	values := make([]int, len(parts))
+	for i, part := range parts {
+		v, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return Stats{}, fmt.Errorf("invalid value %q", part)
+		}
+		values[i] = v
+	}
+	if len(values) == 1 {
+		return Uniform(values[0]), nil
+	}
+	var out Stats
+	for i, name := range Names {
+		out.Set(name, values[i])
+	}
+	return out, nil
+}
@@ -0,0 +1,148 @@
+package stats
+
+import (
+	"reflect"
+	"testing"
+)
+
+// garchomp holds Garchomp's base stats.
+var garchomp = Stats{HP: 108, Attack: 130, Defense: 95, SpecialAttack: 80, SpecialDefense: 85, Speed: 102}
+
+func TestStat(t *testing.T) {
+	tests := []struct {
+		name     string
+		stat     string
+		base     int
+		level    int
+		iv, ev   int
+		modifier float64
+		want     int
+	}{
+		{"garchomp hp", "hp", 108, 100, 31, 252, 1, 420},
+		{"garchomp attack", "attack", 130, 100, 31, 252, 1, 359},
+		{"garchomp speed jolly", "speed", 102, 100, 31, 252, 1.1, 333},
+		{"garchomp special-attack jolly", "special-attack", 80, 100, 31, 0, 0.9, 176},
+		{"garchomp speed level 50", "speed", 102, 50, 31, 252, 1, 154},
+		{"blissey hp", "hp", 255, 100, 31, 252, 1, 714},
+		{"no ivs or evs", "defense", 95, 100, 0, 0, 1, 195},
+		// The nature never applies to HP
+		{"hp ignores nature", "hp", 108, 100, 31, 252, 1.1, 420},
+		// Shedinja always has 1 HP
+		{"shedinja", "hp", 1, 100, 31, 252, 1, 1},
+		{"shedinja level 1", "hp", 1, 1, 0, 0, 1, 1},
+		{"level 1", "hp", 45, 1, 0, 0, 1, 11},
+	}
+	for _, tt := range tests {
+		if got := Stat(tt.stat, tt.base, tt.level, tt.iv, tt.ev, tt.modifier); got != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCalculate(t *testing.T) {
+	jolly, err := LookupNature("jolly")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		level  int
+		ivs    Stats
+		evs    Stats
+		nature Nature
+		want   Stats
+	}{
+		{
+			name:   "neutral 31/252",
+			level:  100,
+			ivs:    Uniform(MaxIV),
+			evs:    Uniform(MaxEV),
+			nature: DefaultNature,
+			want:   Stats{HP: 420, Attack: 359, Defense: 289, SpecialAttack: 259, SpecialDefense: 269, Speed: 303},
+		},
+		{
+			name:   "jolly 4/252/0/0/0/252",
+			level:  100,
+			ivs:    Uniform(MaxIV),
+			evs:    Stats{HP: 4, Attack: 252, Speed: 252},
+			nature: jolly,
+			want:   Stats{HP: 358, Attack: 359, Defense: 226, SpecialAttack: 176, SpecialDefense: 206, Speed: 333},
+		},
+		{
+			name:   "level 50 no ivs or evs",
+			level:  50,
+			nature: DefaultNature,
+			want:   Stats{HP: 168, Attack: 135, Defense: 100, SpecialAttack: 85, SpecialDefense: 90, Speed: 107},
+		},
+	}
+	for _, tt := range tests {
+		if got := Calculate(garchomp, tt.level, tt.ivs, tt.evs, tt.nature); got != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRange(t *testing.T) {
+	low, high := Range(garchomp, 100)
+	if want := (Stats{HP: 326, Attack: 238, Defense: 175, SpecialAttack: 148, SpecialDefense: 157, Speed: 188}); low != want {
+		t.Errorf("low: got %+v, want %+v", low, want)
+	}
+	if want := (Stats{HP: 420, Attack: 394, Defense: 317, SpecialAttack: 284, SpecialDefense: 295, Speed: 333}); high != want {
+		t.Errorf("high: got %+v, want %+v", high, want)
+	}
+}
+
+func TestNatureModifiers(t *testing.T) {
+	tests := []struct {
+		nature  string
+		neutral bool
+		want    map[string]float64
+	}{
+		{"adamant", false, map[string]float64{"attack": 1.1, "defense": 1, "special-attack": 0.9, "special-defense": 1, "speed": 1}},
+		{"timid", false, map[string]float64{"attack": 0.9, "defense": 1, "special-attack": 1, "special-defense": 1, "speed": 1.1}},
+		// Neutral natures raise and lower the same stat
+		{"hardy", true, map[string]float64{"attack": 1, "defense": 1, "special-attack": 1, "special-defense": 1, "speed": 1}},
+		{"quirky", true, map[string]float64{"attack": 1, "defense": 1, "special-attack": 1, "special-defense": 1, "speed": 1}},
+	}
+	for _, tt := range tests {
+		n, err := LookupNature(tt.nature)
+		if err != nil {
+			t.Errorf("%s: %v", tt.nature, err)
+			continue
+		}
+		if got := n.Modifiers(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.nature, got, tt.want)
+		}
+		if got := n.Neutral(); got != tt.neutral {
+			t.Errorf("%s: got neutral %v, want %v", tt.nature, got, tt.neutral)
+		}
+	}
+}
+
+func TestLookupNature(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"", "hardy"},
+		{"Adamant", "adamant"},
+		{" jolly ", "jolly"},
+		// Localized names
+		{"Firme", "adamant"},
+		{"miedosa", "timid"},
+		{"Hart", "adamant"},
+	}
+	for _, tt := range tests {
+		got, err := LookupNature(tt.name)
+		if err != nil {
+			t.Errorf("%q: %v", tt.name, err)
+			continue
+		}
+		if got.Name != tt.want {
+			t.Errorf("%q: got %s, want %s", tt.name, got.Name, tt.want)
+		}
+	}
+	if _, err := LookupNature("sleepy"); err == nil {
+		t.Error("sleepy: got no error")
+	}
+}
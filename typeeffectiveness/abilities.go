@@ -0,0 +1,69 @@
+package typeeffectiveness
+
+// AbilityEffect describes how an ability changes the damage its holder takes
+// from attack types, as of Generation IV.
+type AbilityEffect struct {
+	// Immune lists attack types the ability makes the holder immune to.
+	Immune []string `json:"immune,omitempty"`
+	// Multipliers lists extra damage multipliers per attack type.
+	Multipliers map[string]float64 `json:"multipliers,omitempty"`
+	// OnlySuperEffective is set for Wonder Guard: only super effective hits land.
+	OnlySuperEffective bool `json:"only_super_effective,omitempty"`
+}
+
+// defensiveAbilities lists the abilities that change type matchups.
+var defensiveAbilities = map[string]AbilityEffect{
+	"levitate":     {Immune: []string{"ground"}},
+	"flash-fire":   {Immune: []string{"fire"}},
+	"volt-absorb":  {Immune: []string{"electric"}},
+	"motor-drive":  {Immune: []string{"electric"}},
+	"water-absorb": {Immune: []string{"water"}},
+	"dry-skin":     {Immune: []string{"water"}, Multipliers: map[string]float64{"fire": 1.25}},
+	"thick-fat":    {Multipliers: map[string]float64{"fire": 0.5, "ice": 0.5}},
+	"heatproof":    {Multipliers: map[string]float64{"fire": 0.5}},
+	"wonder-guard": {OnlySuperEffective: true},
+}
+
+// DefensiveAbility returns the matchup effect of an ability, and false if the
+// ability does not change type matchups.
+func DefensiveAbility(ability string) (AbilityEffect, bool) {
+	effect, ok := defensiveAbilities[ability]
+	return effect, ok
+}
+
+// ApplyAbility adjusts a type effectiveness multiplier for the defender's
+// ability. Abilities that do not affect matchups leave it unchanged.
+func ApplyAbility(ability, atkType string, multiplier float64) float64 {
+	effect, ok := defensiveAbilities[ability]
+	if !ok {
+		return multiplier
+	}
+	for _, t := range effect.Immune {
+		if t == atkType {
+			return 0
+		}
+	}
+	if effect.OnlySuperEffective && multiplier <= 1 {
+		return 0
+	}
+	if m, ok := effect.Multipliers[atkType]; ok {
+		multiplier *= m
+	}
+	return multiplier
+}
+
+// GetEffectivenessWithAbility returns the multiplier of an attack type against
+// a type combination, taking the defender's ability into account.
+func (c *Chart) GetEffectivenessWithAbility(atkType string, defTypes []string, ability string) float64 {
+	return ApplyAbility(ability, atkType, c.GetMultiTypeEffectiveness(atkType, defTypes))
+}
+
+// DefensiveProfileWithAbility returns the multiplier of every attack type
+// against a type combination with the given ability.
+func (c *Chart) DefensiveProfileWithAbility(defTypes []string, ability string) map[string]float64 {
+	profile := c.DefensiveProfile(defTypes)
+	for atkType, multiplier := range profile {
+		profile[atkType] = ApplyAbility(ability, atkType, multiplier)
+	}
+	return profile
+}
@@ -1,14 +1,28 @@
 package typeeffectiveness
 
-// Chart holds the Gen IV type effectiveness data.
+import (
+	"fmt"
+	"strings"
+)
+
+// Chart holds the type effectiveness data of a generation.
 // Data only stores multipliers that differ from 1.0 (neutral).
 type Chart struct {
-	Types []string                      `json:"types"`
-	Data  map[string]map[string]float64 `json:"chart"`
+	Generation int                           `json:"generation"`
+	Types      []string                      `json:"types"`
+	Data       map[string]map[string]float64 `json:"chart"`
 }
 
-// NewChart returns the complete Gen IV type effectiveness chart (17 types, no Fairy).
+// NewChart returns the complete Gen IV type effectiveness chart (17 types, no Fairy)
+// used by HG/SS.
 func NewChart() *Chart {
+	c := newGenIIChart()
+	c.Generation = DefaultGeneration
+	return c
+}
+
+// newGenIIChart returns the 17-type chart shared by Generations II to V.
+func newGenIIChart() *Chart {
 	c := &Chart{
 		Types: []string{
 			"normal", "fire", "water", "electric", "grass",
@@ -19,12 +33,7 @@ func NewChart() *Chart {
 		Data: make(map[string]map[string]float64),
 	}
 
-	set := func(atk, def string, val float64) {
-		if c.Data[atk] == nil {
-			c.Data[atk] = make(map[string]float64)
-		}
-		c.Data[atk][def] = val
-	}
+	set := c.set
 
 	// Normal
 	set("normal", "rock", 0.5)
@@ -173,6 +182,19 @@ func NewChart() *Chart {
 	return c
 }
 
+// set stores a non-neutral multiplier for an attack type against a defense type.
+func (c *Chart) set(atk, def string, val float64) {
+	if c.Data[atk] == nil {
+		c.Data[atk] = make(map[string]float64)
+	}
+	c.Data[atk][def] = val
+}
+
+// unset restores a combination to neutral (1.0).
+func (c *Chart) unset(atk, def string) {
+	delete(c.Data[atk], def)
+}
+
 // GetEffectiveness returns the type effectiveness multiplier for an attack type
 // against a defense type. Returns 1.0 (neutral) if the combination is not in the chart.
 func (c *Chart) GetEffectiveness(attackType, defenseType string) float64 {
@@ -183,3 +205,89 @@ func (c *Chart) GetEffectiveness(attackType, defenseType string) float64 {
 	}
 	return 1.0
 }
+
+// IsValidType reports whether typeName is one of the chart's types.
+func (c *Chart) IsValidType(typeName string) bool {
+	for _, t := range c.Types {
+		if t == typeName {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateDefenseTypes checks that a defender has one or two distinct types
+// that exist in the chart.
+func (c *Chart) ValidateDefenseTypes(defenseTypes []string) error {
+	if len(defenseTypes) == 0 || len(defenseTypes) > 2 {
+		return fmt.Errorf("a defender must have one or two types, got %d", len(defenseTypes))
+	}
+	for _, t := range defenseTypes {
+		if !c.IsValidType(t) {
+			return fmt.Errorf("unknown type: %s", t)
+		}
+	}
+	if len(defenseTypes) == 2 && defenseTypes[0] == defenseTypes[1] {
+		return fmt.Errorf("duplicate type: %s", defenseTypes[0])
+	}
+	return nil
+}
+
+// GetMultiTypeEffectiveness returns the multiplier of an attack type against a
+// defender with one or more types. The individual factors are multiplied, and an
+// immunity against any of the types results in 0 regardless of the others.
+func (c *Chart) GetMultiTypeEffectiveness(attackType string, defenseTypes []string) float64 {
+	multiplier := 1.0
+	for _, defType := range defenseTypes {
+		factor := c.GetEffectiveness(attackType, defType)
+		if factor == 0 {
+			return 0
+		}
+		multiplier *= factor
+	}
+	return multiplier
+}
+
+// DefensiveProfile returns the multiplier of every attack type against a
+// defender with the given types.
+func (c *Chart) DefensiveProfile(defenseTypes []string) map[string]float64 {
+	profile := make(map[string]float64, len(c.Types))
+	for _, atkType := range c.Types {
+		profile[atkType] = c.GetMultiTypeEffectiveness(atkType, defenseTypes)
+	}
+	return profile
+}
+
+// Combinations returns every single type and every dual type combination,
+// in chart order.
+func (c *Chart) Combinations() [][]string {
+	var combos [][]string
+	for i, first := range c.Types {
+		combos = append(combos, []string{first})
+		for _, second := range c.Types[i+1:] {
+			combos = append(combos, []string{first, second})
+		}
+	}
+	return combos
+}
+
+// CombinationKey joins defending types into the key used by CombinationMatrix.
+func CombinationKey(defenseTypes []string) string {
+	return strings.Join(defenseTypes, "/")
+}
+
+// CombinationMatrix returns the multiplier of every attack type against every
+// type combination. The outer key is the attack type and the inner key is the
+// combination as returned by CombinationKey, e.g. "water/ground".
+func (c *Chart) CombinationMatrix() map[string]map[string]float64 {
+	combos := c.Combinations()
+	matrix := make(map[string]map[string]float64, len(c.Types))
+	for _, atkType := range c.Types {
+		row := make(map[string]float64, len(combos))
+		for _, combo := range combos {
+			row[CombinationKey(combo)] = c.GetMultiTypeEffectiveness(atkType, combo)
+		}
+		matrix[atkType] = row
+	}
+	return matrix
+}
@@ -0,0 +1,114 @@
+package typeeffectiveness
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultGeneration is the generation of HG/SS, used when none is requested.
+const DefaultGeneration = 4
+
+// MaxGeneration is the latest generation with a known type chart.
+const MaxGeneration = 9
+
+// generationCharts maps ranges of generations to the chart they share.
+// Charts only change in Generation II (Dark/Steel) and Generation VI (Fairy).
+var generationCharts = []struct {
+	From, To int
+	Build    func() *Chart
+}{
+	{From: 1, To: 1, Build: newGenIChart},
+	{From: 2, To: 5, Build: newGenIIChart},
+	{From: 6, To: MaxGeneration, Build: newGenVIChart},
+}
+
+// ForGeneration returns the type effectiveness chart used in the given generation.
+func ForGeneration(gen int) (*Chart, error) {
+	for _, entry := range generationCharts {
+		if gen >= entry.From && gen <= entry.To {
+			c := entry.Build()
+			c.Generation = gen
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported generation: %d (must be between 1 and %d)", gen, MaxGeneration)
+}
+
+// ParseGeneration parses a generation number such as "4" or a roman numeral such
+// as "iv". An empty string returns DefaultGeneration.
+func ParseGeneration(s string) (int, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.TrimPrefix(s, "generation-")
+	if s == "" {
+		return DefaultGeneration, nil
+	}
+	if gen, err := strconv.Atoi(s); err == nil {
+		return gen, nil
+	}
+	romans := []string{"i", "ii", "iii", "iv", "v", "vi", "vii", "viii", "ix"}
+	for i, roman := range romans {
+		if s == roman {
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid generation: %s", s)
+}
+
+// newGenIChart returns the 15-type Generation I chart. It has no Dark or Steel
+// types and keeps the original quirks: Ghost has no effect on Psychic, Bug and
+// Poison are super effective against each other, and Fire does not resist Ice.
+func newGenIChart() *Chart {
+	c := newGenIIChart()
+
+	var types []string
+	for _, t := range c.Types {
+		if t == "dark" || t == "steel" {
+			continue
+		}
+		types = append(types, t)
+	}
+	c.Types = types
+
+	delete(c.Data, "dark")
+	delete(c.Data, "steel")
+	for atk := range c.Data {
+		c.unset(atk, "dark")
+		c.unset(atk, "steel")
+	}
+
+	c.set("ghost", "psychic", 0)
+	c.set("bug", "poison", 2)
+	c.set("poison", "bug", 2)
+	c.unset("ice", "fire")
+
+	return c
+}
+
+// newGenVIChart returns the 18-type chart used since Generation VI. It adds the
+// Fairy type, and Steel no longer resists Ghost and Dark.
+func newGenVIChart() *Chart {
+	c := newGenIIChart()
+	c.Types = append(c.Types, "fairy")
+
+	c.unset("ghost", "steel")
+	c.unset("dark", "steel")
+
+	// Fairy attacking
+	c.set("fairy", "fire", 0.5)
+	c.set("fairy", "fighting", 2)
+	c.set("fairy", "poison", 0.5)
+	c.set("fairy", "dragon", 2)
+	c.set("fairy", "dark", 2)
+	c.set("fairy", "steel", 0.5)
+
+	// Fairy defending
+	c.set("fighting", "fairy", 0.5)
+	c.set("poison", "fairy", 2)
+	c.set("bug", "fairy", 0.5)
+	c.set("dragon", "fairy", 0)
+	c.set("dark", "fairy", 0.5)
+	c.set("steel", "fairy", 2)
+
+	return c
+}
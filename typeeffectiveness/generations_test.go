@@ -0,0 +1,133 @@
+package typeeffectiveness
+
+import "testing"
+
+func TestForGenerationDeltas(t *testing.T) {
+	tests := []struct {
+		gen      int
+		atk, def string
+		want     float64
+	}{
+		// Gen I quirks
+		{1, "ghost", "psychic", 0},
+		{2, "ghost", "psychic", 2},
+		{1, "bug", "poison", 2},
+		{2, "bug", "poison", 0.5},
+		{1, "poison", "bug", 2},
+		{2, "poison", "bug", 1},
+		{1, "ice", "fire", 1},
+		{2, "ice", "fire", 0.5},
+		// Steel resists Ghost and Dark until Gen VI
+		{2, "ghost", "steel", 0.5},
+		{5, "ghost", "steel", 0.5},
+		{6, "ghost", "steel", 1},
+		{4, "dark", "steel", 0.5},
+		{6, "dark", "steel", 1},
+		{9, "dark", "steel", 1},
+		// Fairy
+		{6, "fairy", "dragon", 2},
+		{6, "fairy", "dark", 2},
+		{6, "fairy", "fighting", 2},
+		{6, "fairy", "fire", 0.5},
+		{6, "fairy", "steel", 0.5},
+		{6, "dragon", "fairy", 0},
+		{6, "poison", "fairy", 2},
+		{6, "steel", "fairy", 2},
+		{6, "bug", "fairy", 0.5},
+		// Unchanged across generations
+		{1, "water", "fire", 2},
+		{4, "water", "fire", 2},
+		{9, "water", "fire", 2},
+		{1, "normal", "ghost", 0},
+		{9, "normal", "ghost", 0},
+	}
+	for _, tt := range tests {
+		c, err := ForGeneration(tt.gen)
+		if err != nil {
+			t.Fatalf("gen %d: %v", tt.gen, err)
+		}
+		if got := c.GetEffectiveness(tt.atk, tt.def); got != tt.want {
+			t.Errorf("gen %d: %s against %s: got %v, want %v", tt.gen, tt.atk, tt.def, got, tt.want)
+		}
+	}
+}
+
+func TestForGenerationTypes(t *testing.T) {
+	tests := []struct {
+		gen   int
+		count int
+		has   []string
+		lacks []string
+	}{
+		{1, 15, nil, []string{"dark", "steel", "fairy"}},
+		{2, 17, []string{"dark", "steel"}, []string{"fairy"}},
+		{4, 17, []string{"dark", "steel"}, []string{"fairy"}},
+		{6, 18, []string{"dark", "steel", "fairy"}, nil},
+		{MaxGeneration, 18, []string{"fairy"}, nil},
+	}
+	for _, tt := range tests {
+		c, err := ForGeneration(tt.gen)
+		if err != nil {
+			t.Fatalf("gen %d: %v", tt.gen, err)
+		}
+		if c.Generation != tt.gen {
+			t.Errorf("gen %d: got generation %d", tt.gen, c.Generation)
+		}
+		if len(c.Types) != tt.count {
+			t.Errorf("gen %d: got %d types, want %d", tt.gen, len(c.Types), tt.count)
+		}
+		for _, typ := range tt.has {
+			if !c.IsValidType(typ) {
+				t.Errorf("gen %d: %s is not a valid type", tt.gen, typ)
+			}
+		}
+		for _, typ := range tt.lacks {
+			if c.IsValidType(typ) {
+				t.Errorf("gen %d: %s is a valid type", tt.gen, typ)
+			}
+			// Attacks of removed types and attacks against them are dropped
+			if _, ok := c.Data[typ]; ok {
+				t.Errorf("gen %d: chart has %s attacks", tt.gen, typ)
+			}
+			for atk, defenders := range c.Data {
+				if _, ok := defenders[typ]; ok {
+					t.Errorf("gen %d: chart has %s against %s", tt.gen, atk, typ)
+				}
+			}
+		}
+	}
+}
+
+func TestForGenerationUnsupported(t *testing.T) {
+	for _, gen := range []int{0, -1, MaxGeneration + 1} {
+		if _, err := ForGeneration(gen); err == nil {
+			t.Errorf("gen %d: got no error", gen)
+		}
+	}
+}
+
+func TestParseGeneration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"", DefaultGeneration},
+		{"4", 4},
+		{"iv", 4},
+		{"VI", 6},
+		{"generation-ii", 2},
+	}
+	for _, tt := range tests {
+		got, err := ParseGeneration(tt.in)
+		if err != nil {
+			t.Errorf("%q: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%q: got %d, want %d", tt.in, got, tt.want)
+		}
+	}
+	if _, err := ParseGeneration("x"); err == nil {
+		t.Error(`"x": got no error`)
+	}
+}